@@ -16,6 +16,7 @@ type (
 		MYSQL `yaml:"mysql"`
 		Cors  `yaml:"cors"`
 		Redis `yaml:"redis"`
+		Money `yaml:"money"`
 	}
 
 	// App -.
@@ -51,6 +52,12 @@ type (
 		RedisPassword string `yaml:"redis_password" env:"REDIS_PASSWORD"`
 		RedisDB       int    `yaml:"redis_db" env:"REDIS_DB"`
 	}
+
+	// Money -.
+	Money struct {
+		RoundingMode    string `yaml:"rounding_mode" env:"MONEY_ROUNDING_MODE" env-default:"half_up"`
+		DefaultCurrency string `yaml:"default_currency" env:"MONEY_DEFAULT_CURRENCY" env-default:"VND"`
+	}
 )
 
 // NewConfig returns app config.
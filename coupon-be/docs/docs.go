@@ -54,9 +54,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/coupons": {
+        "/v1/campaigns": {
             "get": {
-                "description": "Get all coupons with pagination",
+                "description": "Get all campaigns with pagination, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -64,10 +64,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupons"
+                    "Campaigns"
                 ],
-                "summary": "Get all coupons",
-                "operationId": "getCoupons",
+                "summary": "Get all campaigns",
+                "operationId": "getCampaigns",
                 "parameters": [
                     {
                         "type": "integer",
@@ -80,19 +80,13 @@ const docTemplate = `{
                         "description": "Limit for pagination",
                         "name": "limit",
                         "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by coupon code",
-                        "name": "coupon_code",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.PaginationResponse-schema_CouponResponse"
+                            "$ref": "#/definitions/schema.PaginationResponse-schema_CampaignResponse"
                         }
                     },
                     "500": {
@@ -104,7 +98,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new coupon",
+                "description": "Create a campaign to group coupons under one budget, date range and owner",
                 "consumes": [
                     "application/json"
                 ],
@@ -112,18 +106,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupons"
+                    "Campaigns"
                 ],
-                "summary": "Create a new coupon",
-                "operationId": "createCoupon",
+                "summary": "Create a campaign",
+                "operationId": "createCampaign",
                 "parameters": [
                     {
-                        "description": "Coupon data",
-                        "name": "coupon",
+                        "description": "Campaign data",
+                        "name": "campaign",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.CreateCouponRequest"
+                            "$ref": "#/definitions/schema.CreateCampaignRequest"
                         }
                     }
                 ],
@@ -131,7 +125,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.Response-schema_CouponResponse"
+                            "$ref": "#/definitions/schema.Response-schema_CampaignResponse"
                         }
                     },
                     "400": {
@@ -149,9 +143,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/coupons/{id}": {
+        "/v1/campaigns/{id}": {
             "get": {
-                "description": "Get a coupon by its ID",
+                "description": "Get a campaign by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -159,14 +153,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupons"
+                    "Campaigns"
                 ],
-                "summary": "Get a coupon by ID",
-                "operationId": "getCouponByID",
+                "summary": "Get a campaign by ID",
+                "operationId": "getCampaignByID",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Coupon ID",
+                        "type": "integer",
+                        "description": "Campaign ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -176,7 +170,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.Response-CouponResponse"
+                            "$ref": "#/definitions/schema.Response-schema_CampaignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
                         }
                     },
                     "404": {
@@ -194,7 +194,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update a coupon by its ID",
+                "description": "Update the fields set in the request and leave the others unchanged",
                 "consumes": [
                     "application/json"
                 ],
@@ -202,25 +202,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupons"
+                    "Campaigns"
                 ],
-                "summary": "Update a coupon",
-                "operationId": "updateCoupon",
+                "summary": "Update a campaign",
+                "operationId": "updateCampaign",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Coupon ID",
+                        "type": "integer",
+                        "description": "Campaign ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated coupon data",
-                        "name": "coupon",
+                        "description": "Updated campaign data",
+                        "name": "campaign",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/schema.UpdateCouponRequest"
+                            "$ref": "#/definitions/schema.UpdateCampaignRequest"
                         }
                     }
                 ],
@@ -228,7 +228,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.CouponResponse"
+                            "$ref": "#/definitions/schema.Response-schema_CampaignResponse"
                         }
                     },
                     "400": {
@@ -252,7 +252,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete a coupon by its ID",
+                "description": "Delete a campaign that no longer has coupons",
                 "consumes": [
                     "application/json"
                 ],
@@ -260,14 +260,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Coupons"
+                    "Campaigns"
                 ],
-                "summary": "Delete a coupon",
-                "operationId": "deleteCoupon",
+                "summary": "Delete a campaign",
+                "operationId": "deleteCampaign",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Coupon ID",
+                        "type": "integer",
+                        "description": "Campaign ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -277,7 +277,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.ModifyDataResponse"
+                            "$ref": "#/definitions/schema.Response-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
                         }
                     },
                     "404": {
@@ -295,9 +301,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/orders/mock": {
-            "post": {
-                "description": "Create a mock order with optional coupon code",
+        "/v1/campaigns/{id}/stats": {
+            "get": {
+                "description": "Aggregate the redemptions of every coupon of a campaign",
                 "consumes": [
                     "application/json"
                 ],
@@ -305,26 +311,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Orders"
+                    "Campaigns"
                 ],
-                "summary": "Create a mock order",
-                "operationId": "createMockOrder",
+                "summary": "Get campaign stats",
+                "operationId": "getCampaignStats",
                 "parameters": [
                     {
-                        "description": "Order data",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/schema.CreateMockOrderRequest"
-                        }
+                        "type": "integer",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/schema.Response-schema_CreateMockOrderResponse"
+                            "$ref": "#/definitions/schema.Response-schema_CampaignStatsResponse"
                         }
                     },
                     "400": {
@@ -333,6 +337,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/schema.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -341,112 +351,1984 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "schema.CouponResponse": {
-            "type": "object",
-            "properties": {
-                "coupon_code": {
-                    "type": "string"
-                },
-                "coupon_type": {
-                    "type": "string"
-                },
-                "coupon_value": {
-                    "type": "number"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "expired_at": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "usage": {
-                    "type": "string"
-                }
-            }
         },
-        "schema.CreateCouponRequest": {
-            "type": "object",
-            "required": [
-                "coupon_code",
-                "coupon_type",
-                "coupon_value",
-                "description",
-                "expired_at",
-                "title",
-                "usage"
-            ],
-            "properties": {
-                "coupon_code": {
-                    "type": "string"
-                },
-                "coupon_type": {
-                    "type": "string",
-                    "enum": [
-                        "fixed",
-                        "percentage"
-                    ]
-                },
-                "coupon_value": {
-                    "type": "number"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "expired_at": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "usage": {
-                    "type": "string"
+        "/v1/coupon-batches/{id}": {
+            "get": {
+                "description": "Get the status and progress of a coupon batch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupon Batches"
+                ],
+                "summary": "Get a coupon batch",
+                "operationId": "getCouponBatch",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CouponBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "schema.CreateMockOrderRequest": {
-            "type": "object",
-            "required": [
-                "cost",
-                "created_at"
-            ],
-            "properties": {
-                "cost": {
-                    "type": "number"
-                },
-                "coupon_code": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+        "/v1/coupon-batches/{id}/download": {
+            "get": {
+                "description": "Download the codes of a completed coupon batch as CSV",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Coupon Batches"
+                ],
+                "summary": "Download the codes of a coupon batch",
+                "operationId": "downloadCouponBatch",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV with a coupon_code column",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "schema.CreateMockOrderResponse": {
-            "type": "object",
-            "properties": {
-                "cost": {
-                    "type": "number"
-                },
-                "coupon_code": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "total_amount": {
+        "/v1/coupons": {
+            "get": {
+                "description": "Get all coupons with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Get all coupons",
+                "operationId": "getCoupons",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit for pagination",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by coupon code",
+                        "name": "coupon_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only coupons usable on this channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only coupons usable with this payment method",
+                        "name": "payment_method",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.PaginationResponse-schema_CouponResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new coupon",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Create a new coupon",
+                "operationId": "createCoupon",
+                "parameters": [
+                    {
+                        "description": "Coupon data",
+                        "name": "coupon",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.CreateCouponRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CouponResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/deleted": {
+            "get": {
+                "description": "Get soft-deleted coupons with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Get deleted coupons",
+                "operationId": "getDeletedCoupons",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit for pagination",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.PaginationResponse-schema_CouponResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/recommendations": {
+            "post": {
+                "description": "Evaluate every eligible coupon for an order and return the 20 that save the most, ranked by savings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Recommend coupons for an order",
+                "operationId": "recommendCoupons",
+                "parameters": [
+                    {
+                        "description": "Order data",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.CreateMockOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-array_schema_CouponRecommendationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/{id}": {
+            "get": {
+                "description": "Get a coupon by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Get a coupon by ID",
+                "operationId": "getCouponByID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-CouponResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a coupon by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Update a coupon",
+                "operationId": "updateCoupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated coupon data",
+                        "name": "coupon",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.UpdateCouponRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.CouponResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a coupon by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Delete a coupon",
+                "operationId": "deleteCoupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ModifyDataResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/{id}/archive": {
+            "post": {
+                "description": "Permanently retire a coupon while keeping its history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Archive a coupon",
+                "operationId": "archiveCoupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CouponResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/{id}/batches": {
+            "post": {
+                "description": "Start generating unique single-use codes that copy the offer of the parent coupon. Codes are generated in the background; poll the batch until it is completed, then download them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupon Batches"
+                ],
+                "summary": "Generate single-use codes for a coupon",
+                "operationId": "createCouponBatch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Parent coupon code",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Code template",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.CreateCouponBatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CouponBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/{id}/flash-claims": {
+            "post": {
+                "description": "Claim a coupon with a limited number of claims. The claim is taken from the stock at once and added to the customer's wallet shortly after",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Claim a flash sale coupon",
+                "operationId": "claimFlashSaleCoupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon code",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Customer claiming the coupon",
+                        "name": "claim",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.FlashClaimRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_FlashClaimResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/{id}/flash-sale": {
+            "get": {
+                "description": "Get the claim limit of a coupon with the claims remaining, persisted and still pending",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Get the stock of a flash sale",
+                "operationId": "getFlashSale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon code",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_FlashSaleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/{id}/pause": {
+            "post": {
+                "description": "Temporarily stop a coupon from being redeemed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Pause a coupon",
+                "operationId": "pauseCoupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CouponResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/{id}/restore": {
+            "post": {
+                "description": "Restore a soft-deleted coupon by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Restore a coupon",
+                "operationId": "restoreCoupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CouponResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/coupons/{id}/resume": {
+            "post": {
+                "description": "Make a draft or paused coupon redeemable again",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupons"
+                ],
+                "summary": "Resume a coupon",
+                "operationId": "resumeCoupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Coupon ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CouponResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers/{id}/segments": {
+            "get": {
+                "description": "Get the segments a customer belongs to, used by segment-restricted coupons",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Get the segments of a customer",
+                "operationId": "getCustomerSegments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CustomerSegmentsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace every segment a customer belongs to. An empty list removes the customer from all segments",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Customers"
+                ],
+                "summary": "Replace the segments of a customer",
+                "operationId": "updateCustomerSegments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Customer segments",
+                        "name": "segments",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.UpdateCustomerSegmentsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CustomerSegmentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers/{id}/wallet": {
+            "get": {
+                "description": "List the coupons a customer claimed with their remaining uses. Claims past their expiry are listed as expired",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Get the wallet of a customer",
+                "operationId": "getWallet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "available",
+                            "used",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "description": "Only list claims in this status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_WalletResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Claim a coupon for a customer. Coupons that require claiming can only be redeemed by customers holding an unexpired claim",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallets"
+                ],
+                "summary": "Claim a coupon into a customer's wallet",
+                "operationId": "claimCoupon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Coupon to claim",
+                        "name": "claim",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.ClaimCouponRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_WalletCouponResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders": {
+            "post": {
+                "description": "Create an order with optional coupon code and record the coupon redemption. Without a code the best eligible auto coupon is applied",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Create an order",
+                "operationId": "createOrder",
+                "parameters": [
+                    {
+                        "description": "Order data",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.CreateMockOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/mock": {
+            "post": {
+                "description": "Create a mock order with optional coupon code. Without a code the best eligible auto coupon is applied",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Create a mock order",
+                "operationId": "createMockOrder",
+                "parameters": [
+                    {
+                        "description": "Order data",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/schema.CreateMockOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_CreateMockOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/orders/{id}": {
+            "get": {
+                "description": "Get an order and the coupons redeemed on it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Orders"
+                ],
+                "summary": "Get an order by ID",
+                "operationId": "getOrderByID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/schema.Response-schema_OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/schema.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.CouponBatchStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "CouponBatchStatusPending",
+                "CouponBatchStatusRunning",
+                "CouponBatchStatusCompleted",
+                "CouponBatchStatusFailed"
+            ]
+        },
+        "model.CouponBuyXGetY": {
+            "type": "object",
+            "properties": {
+                "buy": {
+                    "$ref": "#/definitions/model.CouponItemRule"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "get": {
+                    "$ref": "#/definitions/model.CouponItemRule"
+                },
+                "max_sets": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CouponItemRule": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "skus": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.CouponStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "active",
+                "paused",
+                "archived"
+            ],
+            "x-enum-varnames": [
+                "CouponStatusDraft",
+                "CouponStatusActive",
+                "CouponStatusPaused",
+                "CouponStatusArchived"
+            ]
+        },
+        "model.CouponTargeting": {
+            "type": "object",
+            "properties": {
+                "exclude_categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "exclude_skus": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "include_categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "include_skus": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.CouponTier": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number"
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.CouponType": {
+            "type": "string",
+            "enum": [
+                "fixed",
+                "percentage",
+                "free_shipping",
+                "tiered",
+                "buy_x_get_y"
+            ],
+            "x-enum-varnames": [
+                "CouponTypeFixed",
+                "CouponTypePercentage",
+                "CouponTypeFreeShipping",
+                "CouponTypeTiered",
+                "CouponTypeBuyXGetY"
+            ]
+        },
+        "model.CouponUsage": {
+            "type": "string",
+            "enum": [
+                "manual",
+                "auto"
+            ],
+            "x-enum-varnames": [
+                "CouponUsageManual",
+                "CouponUsageAuto"
+            ]
+        },
+        "model.CouponWindow": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.WalletCouponStatus": {
+            "type": "string",
+            "enum": [
+                "available",
+                "used",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "WalletCouponStatusAvailable",
+                "WalletCouponStatusUsed",
+                "WalletCouponStatusExpired"
+            ]
+        },
+        "schema.AppliedCouponResponse": {
+            "type": "object",
+            "properties": {
+                "coupon": {
+                    "$ref": "#/definitions/schema.CouponResponse"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "tier": {
+                    "$ref": "#/definitions/schema.CouponTierResponse"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "schema.CampaignResponse": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.CampaignStatsResponse": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "number"
+                },
+                "budget_remaining": {
+                    "type": "number"
+                },
+                "campaign_id": {
+                    "type": "integer"
+                },
+                "coupons": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customers": {
+                    "type": "integer"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "orders": {
+                    "type": "integer"
+                },
+                "redemptions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.ClaimCouponRequest": {
+            "type": "object",
+            "required": [
+                "coupon_code"
+            ],
+            "properties": {
+                "coupon_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.CouponBatchResponse": {
+            "type": "object",
+            "properties": {
+                "alphabet": {
+                    "type": "string"
+                },
+                "check_digit": {
+                    "type": "boolean"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "generated": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "parent_coupon_code": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.CouponBatchStatus"
+                }
+            }
+        },
+        "schema.CouponRecommendationResponse": {
+            "type": "object",
+            "properties": {
+                "coupon": {
+                    "$ref": "#/definitions/schema.CouponResponse"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "tier": {
+                    "$ref": "#/definitions/schema.CouponTierResponse"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "schema.CouponRedemptionResponse": {
+            "type": "object",
+            "properties": {
+                "auto_applied": {
+                    "type": "boolean"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "schema.CouponResponse": {
+            "type": "object",
+            "properties": {
+                "allow_partial_discount": {
+                    "type": "boolean"
+                },
+                "allowed_customer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "batch_id": {
+                    "type": "integer"
+                },
+                "budget": {
+                    "type": "number"
+                },
+                "buy_x_get_y": {
+                    "$ref": "#/definitions/model.CouponBuyXGetY"
+                },
+                "campaign_id": {
+                    "type": "integer"
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "claim_limit": {
+                    "type": "integer"
+                },
+                "claim_validity_days": {
+                    "type": "integer"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "coupon_type": {
+                    "$ref": "#/definitions/model.CouponType"
+                },
+                "coupon_value": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_segment": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "exclusive": {
+                    "type": "boolean"
+                },
+                "expired_at": {
+                    "type": "string"
+                },
+                "first_order_only": {
+                    "type": "boolean"
+                },
+                "max_discount_amount": {
+                    "type": "number"
+                },
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "max_redemptions_per_customer": {
+                    "type": "integer"
+                },
+                "min_order_amount": {
+                    "type": "number"
+                },
+                "parent_coupon_code": {
+                    "type": "string"
+                },
+                "payment_methods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "requires_claim": {
+                    "type": "boolean"
+                },
+                "stacking_group": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.CouponStatus"
+                },
+                "targeting": {
+                    "$ref": "#/definitions/model.CouponTargeting"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CouponTier"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "usage": {
+                    "$ref": "#/definitions/model.CouponUsage"
+                },
+                "validity_windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CouponWindow"
+                    }
+                }
+            }
+        },
+        "schema.CouponTierResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "schema.CreateCampaignRequest": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "name",
+                "owner",
+                "starts_at"
+            ],
+            "properties": {
+                "budget": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "owner": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.CreateCouponBatchRequest": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "alphabet": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "minLength": 2
+                },
+                "check_digit": {
+                    "type": "boolean"
+                },
+                "length": {
+                    "type": "integer",
+                    "maximum": 64
+                },
+                "prefix": {
+                    "type": "string",
+                    "maxLength": 32
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 100000
+                }
+            }
+        },
+        "schema.CreateCouponRequest": {
+            "type": "object",
+            "required": [
+                "allowed_customer_ids",
+                "channels",
+                "coupon_code",
+                "coupon_type",
+                "description",
+                "expired_at",
+                "payment_methods",
+                "title",
+                "usage"
+            ],
+            "properties": {
+                "allow_partial_discount": {
+                    "type": "boolean"
+                },
+                "allowed_customer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "budget": {
+                    "type": "number"
+                },
+                "buy_x_get_y": {
+                    "$ref": "#/definitions/model.CouponBuyXGetY"
+                },
+                "campaign_id": {
+                    "type": "integer"
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "claim_limit": {
+                    "type": "integer"
+                },
+                "claim_validity_days": {
+                    "type": "integer"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "coupon_type": {
+                    "$ref": "#/definitions/model.CouponType"
+                },
+                "coupon_value": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_segment": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "description": {
+                    "type": "string"
+                },
+                "exclusive": {
+                    "type": "boolean"
+                },
+                "expired_at": {
+                    "type": "string"
+                },
+                "first_order_only": {
+                    "type": "boolean"
+                },
+                "max_discount_amount": {
+                    "type": "number"
+                },
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "max_redemptions_per_customer": {
+                    "type": "integer"
+                },
+                "min_order_amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "payment_methods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "requires_claim": {
+                    "type": "boolean"
+                },
+                "stacking_group": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "draft",
+                        "active"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CouponStatus"
+                        }
+                    ]
+                },
+                "targeting": {
+                    "$ref": "#/definitions/model.CouponTargeting"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CouponTier"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "usage": {
+                    "enum": [
+                        "manual",
+                        "auto"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CouponUsage"
+                        }
+                    ]
+                },
+                "validity_windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CouponWindow"
+                    }
+                }
+            }
+        },
+        "schema.CreateMockOrderRequest": {
+            "type": "object",
+            "required": [
+                "coupon_codes",
+                "created_at"
+            ],
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "coupon_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.OrderItemRequest"
+                    }
+                },
+                "payment_method": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "minLength": 1
+                },
+                "shipping_fee": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "schema.CreateMockOrderResponse": {
+            "type": "object",
+            "properties": {
+                "auto_applied": {
+                    "type": "boolean"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "coupon": {
+                    "$ref": "#/definitions/schema.CouponResponse"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "coupons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.AppliedCouponResponse"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.OrderItemResponse"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "shipping_discount_amount": {
+                    "type": "number"
+                },
+                "shipping_fee": {
+                    "type": "number"
+                },
+                "subtotal": {
                     "type": "number"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "schema.CustomerSegmentsResponse": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "string"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
@@ -462,14 +2344,187 @@ const docTemplate = `{
                 }
             }
         },
+        "schema.FlashClaimRequest": {
+            "type": "object",
+            "required": [
+                "customer_id"
+            ],
+            "properties": {
+                "customer_id": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "schema.FlashClaimResponse": {
+            "type": "object",
+            "properties": {
+                "claimed_at": {
+                    "type": "string"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "integer"
+                }
+            }
+        },
+        "schema.FlashSaleResponse": {
+            "type": "object",
+            "properties": {
+                "claim_limit": {
+                    "type": "integer"
+                },
+                "claimed": {
+                    "type": "integer"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "remaining": {
+                    "type": "integer"
+                }
+            }
+        },
         "schema.ModifyDataResponse": {
             "type": "object",
             "properties": {
                 "id": {
                     "type": "string"
                 },
-                "result": {
-                    "type": "boolean"
+                "result": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "schema.OrderItemRequest": {
+            "type": "object",
+            "required": [
+                "quantity",
+                "sku"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "schema.OrderItemResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "schema.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.OrderItemResponse"
+                    }
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "redemptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.CouponRedemptionResponse"
+                    }
+                },
+                "shipping_discount_amount": {
+                    "type": "number"
+                },
+                "shipping_fee": {
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "schema.PaginationResponse-schema_CampaignResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.CampaignResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "paging": {
+                    "$ref": "#/definitions/schema.Paging"
                 }
             }
         },
@@ -518,6 +2573,65 @@ const docTemplate = `{
                 }
             }
         },
+        "schema.Response-array_schema_CouponRecommendationResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.CouponRecommendationResponse"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Response-schema_CampaignResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.CampaignResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Response-schema_CampaignStatsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.CampaignStatsResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Response-schema_CouponBatchResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.CouponBatchResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "schema.Response-schema_CouponResponse": {
             "type": "object",
             "properties": {
@@ -546,6 +2660,90 @@ const docTemplate = `{
                 }
             }
         },
+        "schema.Response-schema_CustomerSegmentsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.CustomerSegmentsResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Response-schema_FlashClaimResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.FlashClaimResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Response-schema_FlashSaleResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.FlashSaleResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Response-schema_OrderResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.OrderResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Response-schema_WalletCouponResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.WalletCouponResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "schema.Response-schema_WalletResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {
+                    "$ref": "#/definitions/schema.WalletResponse"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
         "schema.Response-string": {
             "type": "object",
             "properties": {
@@ -560,29 +2758,217 @@ const docTemplate = `{
                 }
             }
         },
+        "schema.UpdateCampaignRequest": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "owner": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
         "schema.UpdateCouponRequest": {
             "type": "object",
+            "required": [
+                "allowed_customer_ids",
+                "channels",
+                "payment_methods"
+            ],
             "properties": {
+                "allow_partial_discount": {
+                    "type": "boolean"
+                },
+                "allowed_customer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "budget": {
+                    "type": "number"
+                },
+                "buy_x_get_y": {
+                    "$ref": "#/definitions/model.CouponBuyXGetY"
+                },
+                "campaign_id": {
+                    "type": "integer"
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "claim_limit": {
+                    "type": "integer"
+                },
+                "claim_validity_days": {
+                    "type": "integer"
+                },
+                "clear": {
+                    "description": "Clear lists the nullable fields to set to null. Fields left out of the\nrequest keep their value.",
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "coupon_type": {
-                    "type": "string",
-                    "enum": [
-                        "fixed",
-                        "percentage"
-                    ]
+                    "$ref": "#/definitions/model.CouponType"
                 },
                 "coupon_value": {
                     "type": "number"
                 },
+                "currency": {
+                    "type": "string"
+                },
+                "customer_segment": {
+                    "type": "string",
+                    "minLength": 1
+                },
                 "description": {
                     "type": "string"
                 },
+                "exclusive": {
+                    "type": "boolean"
+                },
                 "expired_at": {
                     "type": "string"
                 },
+                "first_order_only": {
+                    "type": "boolean"
+                },
+                "max_discount_amount": {
+                    "type": "number"
+                },
+                "max_redemptions": {
+                    "type": "integer"
+                },
+                "max_redemptions_per_customer": {
+                    "type": "integer"
+                },
+                "min_order_amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "payment_methods": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "requires_claim": {
+                    "type": "boolean"
+                },
+                "stacking_group": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "targeting": {
+                    "$ref": "#/definitions/model.CouponTargeting"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CouponTier"
+                    }
+                },
+                "time_zone": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string"
                 },
                 "usage": {
+                    "$ref": "#/definitions/model.CouponUsage"
+                },
+                "validity_windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CouponWindow"
+                    }
+                }
+            }
+        },
+        "schema.UpdateCustomerSegmentsRequest": {
+            "type": "object",
+            "required": [
+                "segments"
+            ],
+            "properties": {
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "schema.WalletCouponResponse": {
+            "type": "object",
+            "properties": {
+                "claimed_at": {
+                    "type": "string"
+                },
+                "coupon": {
+                    "$ref": "#/definitions/schema.CouponResponse"
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "remaining_uses": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/model.WalletCouponStatus"
+                }
+            }
+        },
+        "schema.WalletResponse": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "coupons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/schema.WalletCouponResponse"
+                    }
+                },
+                "customer_id": {
                     "type": "string"
                 }
             }
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"coupon-be/config"
 	"coupon-be/internal/controller"
 	"coupon-be/internal/repositories"
@@ -8,16 +9,20 @@ import (
 	"coupon-be/internal/services"
 	"coupon-be/pkg/httpserver"
 	"coupon-be/pkg/logger"
+	"coupon-be/pkg/money"
 	"io"
 	"time"
 
 	"fmt"
 	"os"
 	"os/signal"
+	"reflect"
 	"syscall"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -47,6 +52,17 @@ func Run(cfg *config.Config) {
 	}
 	l := logger.New(cfg.Log.Level)
 
+	if err := money.SetRoundingMode(money.RoundingMode(cfg.Money.RoundingMode)); err != nil {
+		panic(err)
+	}
+	money.SetDefaultCurrency(money.Currency(cfg.Money.DefaultCurrency))
+	// Let binding rules such as gt=0 compare money amounts as numbers.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+			return field.Interface().(money.Amount).Float64()
+		}, money.Amount{})
+	}
+
 	//connect postgres with gorm
 	db, err := gorm.Open(mysql.Open(cfg.MYSQL.URL), &gorm.Config{})
 	if err != nil {
@@ -62,14 +78,39 @@ func Run(cfg *config.Config) {
 
 	// Repositories
 	couponRepo := repositories.NewCouponRepository(db)
+	orderRepo := repositories.NewOrderRepository(db)
+	customerRepo := repositories.NewCustomerRepository(db)
+	couponBatchRepo := repositories.NewCouponBatchRepository(db)
+	campaignRepo := repositories.NewCampaignRepository(db)
+	walletRepo := repositories.NewWalletRepository(db)
 	// middleware
 
 	// Services
-	couponServices := services.NewCouponService(l)
+	couponServices := services.NewCouponService(l, services.CouponServiceDeps{
+		Redemptions:  orderRepo,
+		OrderHistory: orderRepo,
+		Segments:     customerRepo,
+		Campaigns:    campaignRepo,
+		Ledger:       orderRepo,
+		Wallets:      walletRepo,
+		Discounts:    services.DefaultDiscountRegistry(),
+	})
 
 	// Controllers
-	couponController := controller.NewCouponController(l, couponServices, couponRepo, redisClient)
-	orderController := controller.NewOrderController(l, couponRepo, couponServices)
+	couponController := controller.NewCouponController(l, couponServices, couponRepo, campaignRepo, redisClient)
+	orderController := controller.NewOrderController(l, couponRepo, orderRepo, couponServices)
+	customerController := controller.NewCustomerController(l, customerRepo)
+	couponBatchController := controller.NewCouponBatchController(l, couponRepo, couponBatchRepo)
+	campaignController := controller.NewCampaignController(l, campaignRepo)
+	walletController := controller.NewWalletController(l, couponServices, couponRepo, walletRepo)
+	flashClaimController := controller.NewFlashClaimController(l, couponServices, couponController, walletRepo, redisClient)
+
+	// Workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go flashClaimController.Run(workerCtx)
+	go couponBatchController.ResumeCouponBatches(workerCtx)
+
 	// HTTP Server
 	handler := gin.New()
 	handler.Use(cors.New(cors.Config{
@@ -79,7 +120,7 @@ func Run(cfg *config.Config) {
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
-	router.NewRouter(handler, l, couponController, orderController)
+	router.NewRouter(handler, l, couponController, orderController, customerController, couponBatchController, campaignController, walletController, flashClaimController)
 	httpServer := httpserver.New(handler, httpserver.Port(cfg.HTTP.Port))
 
 	// Waiting signal
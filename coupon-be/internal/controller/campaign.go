@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/repositories"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
+	"time"
+)
+
+type CampaignController interface {
+	CreateCampaign(ctx context.Context, req schema.CreateCampaignRequest) (model.Campaign, error)
+	GetCampaignsWithTotal(ctx context.Context, offset, limit int) ([]model.Campaign, int64, error)
+	GetCampaignByID(ctx context.Context, id uint64) (model.Campaign, error)
+	UpdateCampaign(ctx context.Context, id uint64, req schema.UpdateCampaignRequest) (model.Campaign, error)
+	DeleteCampaign(ctx context.Context, id uint64) error
+	GetCampaignStats(ctx context.Context, id uint64) (schema.CampaignStatsResponse, error)
+}
+
+type campaignController struct {
+	l  logger.Interface
+	cp repositories.CampaignRepository
+}
+
+func NewCampaignController(l logger.Interface, cp repositories.CampaignRepository) CampaignController {
+	return &campaignController{
+		l:  l,
+		cp: cp,
+	}
+}
+
+func (c *campaignController) CreateCampaign(ctx context.Context, req schema.CreateCampaignRequest) (model.Campaign, error) {
+	if err := validateCampaignDates(*req.StartsAt, *req.EndsAt); err != nil {
+		return model.Campaign{}, err
+	}
+	campaign := model.Campaign{
+		Name:     *req.Name,
+		Owner:    *req.Owner,
+		Budget:   req.Budget,
+		Currency: money.DefaultCurrency(),
+		StartsAt: *req.StartsAt,
+		EndsAt:   *req.EndsAt,
+	}
+	if req.Description != nil {
+		campaign.Description = *req.Description
+	}
+	if req.Currency != nil {
+		campaign.Currency = *req.Currency
+	}
+	if err := validateCampaignBudget(campaign.Budget, campaign.Currency); err != nil {
+		return model.Campaign{}, err
+	}
+	campaign, err := c.cp.CreateCampaign(ctx, campaign)
+	if err != nil {
+		c.l.Error("Failed to create campaign", "error", err, "name", *req.Name)
+		return model.Campaign{}, err
+	}
+	return campaign, nil
+}
+
+func (c *campaignController) GetCampaignsWithTotal(ctx context.Context, offset, limit int) ([]model.Campaign, int64, error) {
+	return c.cp.GetCampaignsWithTotal(ctx, offset, limit)
+}
+
+func (c *campaignController) GetCampaignByID(ctx context.Context, id uint64) (model.Campaign, error) {
+	return c.cp.GetCampaignByID(ctx, id)
+}
+
+func (c *campaignController) UpdateCampaign(ctx context.Context, id uint64, req schema.UpdateCampaignRequest) (model.Campaign, error) {
+	campaign, err := c.cp.GetCampaignByID(ctx, id)
+	if err != nil {
+		return model.Campaign{}, err
+	}
+	data := map[string]any{"updated_at": time.Now()}
+	if req.Name != nil {
+		data["name"] = *req.Name
+	}
+	if req.Description != nil {
+		data["description"] = *req.Description
+	}
+	if req.Owner != nil {
+		data["owner"] = *req.Owner
+	}
+	if req.Budget != nil {
+		if err := validateCampaignBudget(req.Budget, campaign.Currency); err != nil {
+			return model.Campaign{}, err
+		}
+		data["budget"] = *req.Budget
+	}
+	if req.StartsAt != nil {
+		data["starts_at"] = *req.StartsAt
+		campaign.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		data["ends_at"] = *req.EndsAt
+		campaign.EndsAt = *req.EndsAt
+	}
+	if err := validateCampaignDates(campaign.StartsAt, campaign.EndsAt); err != nil {
+		return model.Campaign{}, err
+	}
+	campaign, err = c.cp.UpdateCampaign(ctx, id, data)
+	if err != nil {
+		c.l.Error("Failed to update campaign", "error", err, "id", id)
+		return model.Campaign{}, err
+	}
+	return campaign, nil
+}
+
+func (c *campaignController) DeleteCampaign(ctx context.Context, id uint64) error {
+	if err := c.cp.DeleteCampaign(ctx, id); err != nil {
+		c.l.Error("Failed to delete campaign", "error", err, "id", id)
+		return err
+	}
+	return nil
+}
+
+func (c *campaignController) GetCampaignStats(ctx context.Context, id uint64) (schema.CampaignStatsResponse, error) {
+	campaign, err := c.cp.GetCampaignByID(ctx, id)
+	if err != nil {
+		return schema.CampaignStatsResponse{}, err
+	}
+	stats, err := c.cp.GetCampaignStats(ctx, id)
+	if err != nil {
+		c.l.Error("Failed to get campaign stats", "error", err, "id", id)
+		return schema.CampaignStatsResponse{}, err
+	}
+	return schema.ToCampaignStatsResponse(campaign, stats), nil
+}
+
+func validateCampaignDates(startsAt, endsAt time.Time) error {
+	if !startsAt.Before(endsAt) {
+		return errs.BadRequestError{Message: "starts_at must be before ends_at"}
+	}
+	return nil
+}
+
+func validateCampaignBudget(budget *money.Amount, currency money.Currency) error {
+	if budget != nil && !budget.FitsMinorUnit(currency) {
+		return errs.BadRequestError{Message: fmt.Sprintf("budget %s has more decimals than %s allows", budget, currency.OrDefault())}
+	}
+	return nil
+}
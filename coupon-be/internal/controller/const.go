@@ -2,4 +2,6 @@ package controller
 
 const (
 	CACHE_EXPIRATION = 3600
+	// MAX_RECOMMENDATIONS is the number of coupons recommended for an order.
+	MAX_RECOMMENDATIONS = 20
 )
@@ -7,6 +7,8 @@ import (
 	"coupon-be/internal/schema"
 	"coupon-be/internal/services"
 	"coupon-be/pkg/logger"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
 	"coupon-be/utils"
 	"fmt"
 	"strconv"
@@ -17,37 +19,107 @@ import (
 
 type CouponController interface {
 	CreateCoupon(ctx context.Context, coupon schema.CreateCouponRequest) (model.Coupon, error)
-	GetCouponsWithTotal(ctx context.Context, offset, limit int, couponCode string) ([]model.Coupon, int64, error)
+	GetCouponsWithTotal(ctx context.Context, offset, limit int, couponCode, channel, paymentMethod string) ([]model.Coupon, int64, error)
 	GetCouponByID(ctx context.Context, id string) (model.Coupon, error)
 	UpdateCoupon(ctx context.Context, id string, coupon schema.UpdateCouponRequest) (model.Coupon, error)
 	DeleteCoupon(ctx context.Context, id string) error
+	ChangeCouponStatus(ctx context.Context, id string, status model.CouponStatus) (model.Coupon, error)
+	RestoreCoupon(ctx context.Context, id string) (model.Coupon, error)
+	GetDeletedCouponsWithTotal(ctx context.Context, offset, limit int) ([]model.Coupon, int64, error)
+	RecommendCoupons(ctx context.Context, req schema.CreateMockOrderRequest) ([]schema.CouponRecommendationResponse, error)
 }
 
 type couponControllerImpl struct {
 	l     logger.Interface
 	cs    services.CouponService
 	cr    repositories.CouponRepository
+	cp    repositories.CampaignRepository
 	redis *redis.Client
 }
 
-func NewCouponController(l logger.Interface, cs services.CouponService, cr repositories.CouponRepository, rc *redis.Client) CouponController {
+func NewCouponController(l logger.Interface, cs services.CouponService, cr repositories.CouponRepository, cp repositories.CampaignRepository, rc *redis.Client) CouponController {
 	return &couponControllerImpl{
 		l:     l,
 		cs:    cs,
 		cr:    cr,
+		cp:    cp,
 		redis: rc,
 	}
 }
 
 func (c *couponControllerImpl) CreateCoupon(ctx context.Context, coupon schema.CreateCouponRequest) (model.Coupon, error) {
+	if err := validateCouponWindow(coupon.StartsAt, coupon.ExpiredAt); err != nil {
+		return model.Coupon{}, err
+	}
 	couponModel := model.Coupon{
-		CouponCode:  *coupon.CouponCode,
-		Title:       *coupon.Title,
-		Description: *coupon.Description,
-		CouponType:  *coupon.CouponType,
-		Usage:       *coupon.Usage,
-		ExpiredAt:   *coupon.ExpiredAt,
-		CouponValue: *coupon.CouponValue,
+		CouponCode:                *coupon.CouponCode,
+		Title:                     *coupon.Title,
+		Description:               *coupon.Description,
+		CouponType:                *coupon.CouponType,
+		Usage:                     *coupon.Usage,
+		Status:                    model.CouponStatusActive,
+		StackingGroup:             coupon.StackingGroup,
+		CustomerSegment:           coupon.CustomerSegment,
+		StartsAt:                  coupon.StartsAt,
+		ExpiredAt:                 *coupon.ExpiredAt,
+		TimeZone:                  coupon.TimeZone,
+		Currency:                  money.DefaultCurrency(),
+		MaxRedemptions:            coupon.MaxRedemptions,
+		MaxRedemptionsPerCustomer: coupon.MaxRedemptionsPerCustomer,
+		MinOrderAmount:            coupon.MinOrderAmount,
+		MaxDiscountAmount:         coupon.MaxDiscountAmount,
+		Budget:                    coupon.Budget,
+		ClaimValidityDays:         coupon.ClaimValidityDays,
+		ClaimLimit:                coupon.ClaimLimit,
+		CampaignID:                coupon.CampaignID,
+	}
+
+	if coupon.Status != nil {
+		couponModel.Status = *coupon.Status
+	}
+	if coupon.Exclusive != nil {
+		couponModel.Exclusive = *coupon.Exclusive
+	}
+	if coupon.Priority != nil {
+		couponModel.Priority = *coupon.Priority
+	}
+	if coupon.FirstOrderOnly != nil {
+		couponModel.FirstOrderOnly = *coupon.FirstOrderOnly
+	}
+	if coupon.AllowPartialDiscount != nil {
+		couponModel.AllowPartialDiscount = *coupon.AllowPartialDiscount
+	}
+	if coupon.RequiresClaim != nil {
+		couponModel.RequiresClaim = *coupon.RequiresClaim
+	}
+	if coupon.Currency != nil {
+		couponModel.Currency = *coupon.Currency
+	}
+	if coupon.CouponValue != nil {
+		couponModel.CouponValue = *coupon.CouponValue
+	}
+	if coupon.Tiers != nil {
+		couponModel.Tiers = *coupon.Tiers
+	}
+	if coupon.ValidityWindows != nil {
+		couponModel.ValidityWindows = *coupon.ValidityWindows
+	}
+	if coupon.AllowedCustomerIDs != nil {
+		couponModel.AllowedCustomerIDs = *coupon.AllowedCustomerIDs
+	}
+	if coupon.Channels != nil {
+		couponModel.Channels = *coupon.Channels
+	}
+	if coupon.PaymentMethods != nil {
+		couponModel.PaymentMethods = *coupon.PaymentMethods
+	}
+	couponModel.Targeting = coupon.Targeting
+	couponModel.BuyXGetY = coupon.BuyXGetY
+	if err := c.cs.ValidateCouponParams(ctx, couponModel); err != nil {
+		return model.Coupon{}, errs.BadRequestError{Message: err.Error()}
+	}
+	if err := c.validateCampaign(ctx, couponModel.CampaignID, couponModel.Currency); err != nil {
+		return model.Coupon{}, err
 	}
 
 	couponResponse, err := c.cr.CreateCoupon(ctx, couponModel)
@@ -58,9 +130,9 @@ func (c *couponControllerImpl) CreateCoupon(ctx context.Context, coupon schema.C
 	return couponResponse, nil
 }
 
-func (c *couponControllerImpl) GetCouponsWithTotal(ctx context.Context, offset, limit int, couponCode string) ([]model.Coupon, int64, error) {
-	if couponCode != "" {
-		return c.cr.SearchCouponsWithTotal(ctx, offset, limit, couponCode)
+func (c *couponControllerImpl) GetCouponsWithTotal(ctx context.Context, offset, limit int, couponCode, channel, paymentMethod string) ([]model.Coupon, int64, error) {
+	if couponCode != "" || channel != "" || paymentMethod != "" {
+		return c.cr.SearchCouponsWithTotal(ctx, offset, limit, couponCode, channel, paymentMethod)
 	}
 	return c.cr.GetCouponsWithTotal(ctx, offset, limit)
 }
@@ -96,33 +168,57 @@ func (c *couponControllerImpl) GetCouponByID(ctx context.Context, id string) (mo
 }
 
 func (c *couponControllerImpl) UpdateCoupon(ctx context.Context, id string, coupon schema.UpdateCouponRequest) (model.Coupon, error) {
-	couponMap := utils.StructToMapGetNull(coupon)
+	if err := validateCouponWindow(coupon.StartsAt, coupon.ExpiredAt); err != nil {
+		return model.Coupon{}, err
+	}
+	// Only the fields sent are updated, and the ones listed in Clear set to
+	// null.
+	couponMap := utils.StructToMap(coupon)
+	for _, column := range coupon.Clear {
+		if _, ok := couponMap[column]; ok {
+			return model.Coupon{}, errs.BadRequestError{Message: fmt.Sprintf("%s cannot be both set and cleared", column)}
+		}
+		couponMap[column] = nil
+	}
+	if err := c.validateUpdatedCouponParams(ctx, id, coupon); err != nil {
+		return model.Coupon{}, err
+	}
+	if err := c.validateUpdatedCouponCampaign(ctx, id, coupon); err != nil {
+		return model.Coupon{}, err
+	}
 	couponMap["updated_at"] = time.Now()
 	couponResponse, err := c.cr.UpdateCoupon(ctx, id, couponMap)
 	if err != nil {
 		return model.Coupon{}, err
 	}
 	go func() {
-		ctx1 := context.Background()
-		hashKey := "coupon:" + id
-		couponMap := utils.StructToMapGetNull(couponResponse)
-		err := c.redis.HSet(ctx1, hashKey, couponMap).Err()
-		if err != nil {
-			c.l.Error("Failed to update cached coupon", "error", err, "id", id)
-			return
-		} else {
-			c.l.Info("Updated cached coupon successfully", "id", id)
-		}
-		_, err = c.redis.Expire(ctx1, hashKey, CACHE_EXPIRATION*time.Second).Result()
-		if err != nil {
-			c.l.Error("Failed to set expiration for updated cached coupon", "error", err, "id", id)
-		} else {
-			c.l.Info("Set expiration for updated cached coupon successfully", "id", id, "expiration", CACHE_EXPIRATION)
-		}
+		c.refreshCachedCoupon(couponResponse)
+		c.resetFlashStock(couponResponse.CouponCode)
 	}()
 	return couponResponse, nil
 }
 
+func (c *couponControllerImpl) ChangeCouponStatus(ctx context.Context, id string, status model.CouponStatus) (model.Coupon, error) {
+	coupon, err := c.cr.GetCouponByID(ctx, id)
+	if err != nil {
+		c.l.Error("Failed to get coupon by ID", "error", err, "id", id)
+		return model.Coupon{}, err
+	}
+	if err := c.cs.ValidateStatusTransition(ctx, coupon, status); err != nil {
+		return model.Coupon{}, errs.BadRequestError{Message: err.Error()}
+	}
+	couponResponse, err := c.cr.UpdateCoupon(ctx, id, map[string]any{
+		"status":     status,
+		"updated_at": time.Now(),
+	})
+	if err != nil {
+		c.l.Error("Failed to change coupon status", "error", err, "id", id, "status", status)
+		return model.Coupon{}, err
+	}
+	go c.refreshCachedCoupon(couponResponse)
+	return couponResponse, nil
+}
+
 func (c *couponControllerImpl) DeleteCoupon(ctx context.Context, id string) error {
 	if err := c.cr.DeleteCoupon(ctx, id); err != nil {
 		c.l.Error("Failed to delete coupon", "error", err, "id", id)
@@ -141,6 +237,75 @@ func (c *couponControllerImpl) DeleteCoupon(ctx context.Context, id string) erro
 	return nil
 }
 
+func (c *couponControllerImpl) RestoreCoupon(ctx context.Context, id string) (model.Coupon, error) {
+	coupon, err := c.cr.RestoreCoupon(ctx, id)
+	if err != nil {
+		c.l.Error("Failed to restore coupon", "error", err, "id", id)
+		return model.Coupon{}, err
+	}
+	go c.refreshCachedCoupon(coupon)
+	return coupon, nil
+}
+
+func (c *couponControllerImpl) GetDeletedCouponsWithTotal(ctx context.Context, offset, limit int) ([]model.Coupon, int64, error) {
+	return c.cr.GetDeletedCouponsWithTotal(ctx, offset, limit)
+}
+
+func (c *couponControllerImpl) RecommendCoupons(ctx context.Context, req schema.CreateMockOrderRequest) ([]schema.CouponRecommendationResponse, error) {
+	req, err := normalizeOrderRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	coupons, err := c.cr.GetActiveCoupons(ctx, req.CreatedAt)
+	if err != nil {
+		c.l.Error("Failed to get active coupons", "error", err)
+		return nil, err
+	}
+	ranked := c.cs.RankCoupons(ctx, coupons, req)
+	if len(ranked) > MAX_RECOMMENDATIONS {
+		ranked = ranked[:MAX_RECOMMENDATIONS]
+	}
+	recommendations := make([]schema.CouponRecommendationResponse, len(ranked))
+	for i, selection := range ranked {
+		recommendations[i] = schema.CouponRecommendationResponse{
+			Rank:           i + 1,
+			DiscountAmount: req.OrderTotal().Sub(selection.TotalAmount),
+			TotalAmount:    selection.TotalAmount,
+			Currency:       req.OrderCurrency(),
+			Tier:           schema.ToCouponTierResponse(selection.Coupon, selection.Tier),
+			Coupon:         schema.ToCouponResponse(selection.Coupon),
+		}
+	}
+	return recommendations, nil
+}
+
+func (c *couponControllerImpl) refreshCachedCoupon(coupon model.Coupon) {
+	ctx := context.Background()
+	hashKey := "coupon:" + coupon.CouponCode
+	couponMap := utils.StructToMapGetNull(coupon)
+	err := c.redis.HSet(ctx, hashKey, couponMap).Err()
+	if err != nil {
+		c.l.Error("Failed to update cached coupon", "error", err, "id", coupon.CouponCode)
+		return
+	} else {
+		c.l.Info("Updated cached coupon successfully", "id", coupon.CouponCode)
+	}
+	_, err = c.redis.Expire(ctx, hashKey, CACHE_EXPIRATION*time.Second).Result()
+	if err != nil {
+		c.l.Error("Failed to set expiration for updated cached coupon", "error", err, "id", coupon.CouponCode)
+	} else {
+		c.l.Info("Set expiration for updated cached coupon successfully", "id", coupon.CouponCode, "expiration", CACHE_EXPIRATION)
+	}
+}
+
+// resetFlashStock drops the Redis stock of a flash sale so the next claim
+// reconciles it with the coupon's current claim limit.
+func (c *couponControllerImpl) resetFlashStock(couponCode string) {
+	if err := c.redis.Del(context.Background(), flashStockKey(couponCode)).Err(); err != nil {
+		c.l.Error("Failed to reset flash sale stock", "error", err, "id", couponCode)
+	}
+}
+
 func (c *couponControllerImpl) getCouponFromCache(ctx context.Context, id string) (model.Coupon, error) {
 	hashKey := "coupon:" + id
 	couponHash, err := c.redis.HGetAll(ctx, hashKey).Result()
@@ -148,10 +313,34 @@ func (c *couponControllerImpl) getCouponFromCache(ctx context.Context, id string
 		return model.Coupon{}, fmt.Errorf("coupon not found in cache: %w", err)
 	}
 
-	couponValue, err := strconv.ParseFloat(couponHash["coupon_value"], 64)
+	exclusive, err := parseOptionalBool(couponHash["exclusive"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid exclusive in cache: %w", err)
+	}
+	priority, err := parseOptionalInt(couponHash["priority"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid priority in cache: %w", err)
+	}
+	firstOrderOnly, err := parseOptionalBool(couponHash["first_order_only"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid first_order_only in cache: %w", err)
+	}
+	batchID, err := parseOptionalUint(couponHash["batch_id"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid batch_id in cache: %w", err)
+	}
+	campaignID, err := parseOptionalUint(couponHash["campaign_id"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid campaign_id in cache: %w", err)
+	}
+	couponValue, err := money.NewFromString(couponHash["coupon_value"])
 	if err != nil {
 		return model.Coupon{}, fmt.Errorf("invalid coupon value in cache: %w", err)
 	}
+	startsAt, err := parseOptionalTime(couponHash["starts_at"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid starts_at in cache: %w", err)
+	}
 	expiredAt, err := utils.ParseTime(couponHash["expired_at"])
 	if err != nil {
 		return model.Coupon{}, fmt.Errorf("invalid expired_at in cache: %w", err)
@@ -164,16 +353,285 @@ func (c *couponControllerImpl) getCouponFromCache(ctx context.Context, id string
 	if err != nil {
 		return model.Coupon{}, fmt.Errorf("invalid updated_at in cache: %w", err)
 	}
+	maxRedemptions, err := parseOptionalInt(couponHash["max_redemptions"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid max_redemptions in cache: %w", err)
+	}
+	maxRedemptionsPerCustomer, err := parseOptionalInt(couponHash["max_redemptions_per_customer"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid max_redemptions_per_customer in cache: %w", err)
+	}
+	minOrderAmount, err := parseOptionalAmount(couponHash["min_order_amount"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid min_order_amount in cache: %w", err)
+	}
+	maxDiscountAmount, err := parseOptionalAmount(couponHash["max_discount_amount"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid max_discount_amount in cache: %w", err)
+	}
+	budget, err := parseOptionalAmount(couponHash["budget"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid budget in cache: %w", err)
+	}
+	allowPartialDiscount, err := parseOptionalBool(couponHash["allow_partial_discount"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid allow_partial_discount in cache: %w", err)
+	}
+	requiresClaim, err := parseOptionalBool(couponHash["requires_claim"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid requires_claim in cache: %w", err)
+	}
+	claimValidityDays, err := parseOptionalInt(couponHash["claim_validity_days"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid claim_validity_days in cache: %w", err)
+	}
+	claimLimit, err := parseOptionalInt(couponHash["claim_limit"])
+	if err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid claim_limit in cache: %w", err)
+	}
+	var tiers model.CouponTiers
+	if err := tiers.UnmarshalBinary([]byte(couponHash["tiers"])); err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid tiers in cache: %w", err)
+	}
+	var targeting *model.CouponTargeting
+	if couponHash["targeting"] != "" {
+		targeting = &model.CouponTargeting{}
+		if err := targeting.UnmarshalBinary([]byte(couponHash["targeting"])); err != nil {
+			return model.Coupon{}, fmt.Errorf("invalid targeting in cache: %w", err)
+		}
+	}
+	var validityWindows model.CouponWindows
+	if err := validityWindows.UnmarshalBinary([]byte(couponHash["validity_windows"])); err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid validity_windows in cache: %w", err)
+	}
+	var allowedCustomerIDs model.StringList
+	if err := allowedCustomerIDs.UnmarshalBinary([]byte(couponHash["allowed_customer_ids"])); err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid allowed_customer_ids in cache: %w", err)
+	}
+	var channels model.StringList
+	if err := channels.UnmarshalBinary([]byte(couponHash["channels"])); err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid channels in cache: %w", err)
+	}
+	var paymentMethods model.StringList
+	if err := paymentMethods.UnmarshalBinary([]byte(couponHash["payment_methods"])); err != nil {
+		return model.Coupon{}, fmt.Errorf("invalid payment_methods in cache: %w", err)
+	}
+	var buyXGetY *model.CouponBuyXGetY
+	if couponHash["buy_x_get_y"] != "" {
+		buyXGetY = &model.CouponBuyXGetY{}
+		if err := buyXGetY.UnmarshalBinary([]byte(couponHash["buy_x_get_y"])); err != nil {
+			return model.Coupon{}, fmt.Errorf("invalid buy_x_get_y in cache: %w", err)
+		}
+	}
 
 	return model.Coupon{
-		CouponCode:  couponHash["coupon_code"],
-		Title:       couponHash["title"],
-		Description: couponHash["description"],
-		CouponType:  model.CouponType(couponHash["coupon_type"]),
-		Usage:       model.CouponUsage(couponHash["usage"]),
-		CouponValue: couponValue,
-		ExpiredAt:   expiredAt,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
+		CouponCode:                couponHash["coupon_code"],
+		Title:                     couponHash["title"],
+		Description:               couponHash["description"],
+		CouponType:                model.CouponType(couponHash["coupon_type"]),
+		Usage:                     model.CouponUsage(couponHash["usage"]),
+		Status:                    model.CouponStatus(couponHash["status"]),
+		StackingGroup:             parseOptionalString(couponHash["stacking_group"]),
+		Exclusive:                 exclusive,
+		Priority:                  derefInt(priority),
+		CouponValue:               couponValue,
+		Currency:                  money.Currency(couponHash["currency"]),
+		Tiers:                     tiers,
+		Targeting:                 targeting,
+		BuyXGetY:                  buyXGetY,
+		AllowedCustomerIDs:        allowedCustomerIDs,
+		CustomerSegment:           parseOptionalString(couponHash["customer_segment"]),
+		FirstOrderOnly:            firstOrderOnly,
+		ParentCouponCode:          parseOptionalString(couponHash["parent_coupon_code"]),
+		BatchID:                   batchID,
+		CampaignID:                campaignID,
+		Channels:                  channels,
+		PaymentMethods:            paymentMethods,
+		StartsAt:                  startsAt,
+		ExpiredAt:                 expiredAt,
+		ValidityWindows:           validityWindows,
+		TimeZone:                  parseOptionalString(couponHash["time_zone"]),
+		MaxRedemptions:            maxRedemptions,
+		MaxRedemptionsPerCustomer: maxRedemptionsPerCustomer,
+		MinOrderAmount:            minOrderAmount,
+		MaxDiscountAmount:         maxDiscountAmount,
+		Budget:                    budget,
+		AllowPartialDiscount:      allowPartialDiscount,
+		RequiresClaim:             requiresClaim,
+		ClaimValidityDays:         claimValidityDays,
+		ClaimLimit:                claimLimit,
+		CreatedAt:                 createdAt,
+		UpdatedAt:                 updatedAt,
 	}, nil
 }
+
+// parseOptionalInt decodes a nullable integer field; nil values are cached as
+// empty strings.
+func parseOptionalInt(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseOptionalAmount(value string) (*money.Amount, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := money.NewFromString(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := utils.ParseTime(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// validateUpdatedCouponParams checks the coupon as it will be after the
+// update, its stored fields merged with the ones sent or cleared, against the
+// rules of its type.
+func (c *couponControllerImpl) validateUpdatedCouponParams(ctx context.Context, id string, req schema.UpdateCouponRequest) error {
+	coupon, err := c.cr.GetCouponByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	cleared := make(map[string]bool, len(req.Clear))
+	for _, column := range req.Clear {
+		cleared[column] = true
+	}
+	if req.CouponType != nil {
+		coupon.CouponType = *req.CouponType
+	}
+	if req.CouponValue != nil {
+		coupon.CouponValue = *req.CouponValue
+	}
+	if req.Currency != nil {
+		coupon.Currency = *req.Currency
+	}
+	coupon.MinOrderAmount = updatedField(coupon.MinOrderAmount, req.MinOrderAmount, cleared["min_order_amount"])
+	coupon.MaxDiscountAmount = updatedField(coupon.MaxDiscountAmount, req.MaxDiscountAmount, cleared["max_discount_amount"])
+	coupon.Budget = updatedField(coupon.Budget, req.Budget, cleared["budget"])
+	if cleared["tiers"] {
+		coupon.Tiers = nil
+	} else if req.Tiers != nil {
+		coupon.Tiers = *req.Tiers
+	}
+	coupon.BuyXGetY = updatedField(coupon.BuyXGetY, req.BuyXGetY, cleared["buy_x_get_y"])
+	if cleared["validity_windows"] {
+		coupon.ValidityWindows = nil
+	} else if req.ValidityWindows != nil {
+		coupon.ValidityWindows = *req.ValidityWindows
+	}
+	coupon.TimeZone = updatedField(coupon.TimeZone, req.TimeZone, cleared["time_zone"])
+	coupon.ClaimLimit = updatedField(coupon.ClaimLimit, req.ClaimLimit, cleared["claim_limit"])
+	if req.RequiresClaim != nil {
+		coupon.RequiresClaim = *req.RequiresClaim
+	}
+	if err := c.cs.ValidateCouponParams(ctx, coupon); err != nil {
+		return errs.BadRequestError{Message: err.Error()}
+	}
+	return nil
+}
+
+// validateUpdatedCouponCampaign checks the campaign an update moves the coupon
+// into against the coupon's currency after the update.
+func (c *couponControllerImpl) validateUpdatedCouponCampaign(ctx context.Context, id string, req schema.UpdateCouponRequest) error {
+	if req.CampaignID == nil {
+		return nil
+	}
+	currency := req.Currency
+	if currency == nil {
+		coupon, err := c.cr.GetCouponByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		currency = &coupon.Currency
+	}
+	return c.validateCampaign(ctx, req.CampaignID, *currency)
+}
+
+// validateCampaign checks that the campaign a coupon belongs to exists and
+// runs in the coupon's currency, so its stats and budget add up.
+func (c *couponControllerImpl) validateCampaign(ctx context.Context, campaignID *uint64, currency money.Currency) error {
+	if campaignID == nil {
+		return nil
+	}
+	campaign, err := c.cp.GetCampaignByID(ctx, *campaignID)
+	if err != nil {
+		if _, ok := err.(errs.NotFoundError); ok {
+			return errs.BadRequestError{Message: err.Error()}
+		}
+		c.l.Error("Failed to get campaign", "error", err, "campaign_id", *campaignID)
+		return err
+	}
+	if campaign.Currency.OrDefault() != currency.OrDefault() {
+		return errs.BadRequestError{
+			Message: fmt.Sprintf("coupon currency %s does not match the %s currency of campaign %d", currency.OrDefault(), campaign.Currency.OrDefault(), campaign.ID),
+		}
+	}
+	return nil
+}
+
+// updatedField returns a nullable field after an update: null if cleared,
+// the value sent if any, the stored value otherwise.
+func updatedField[T any](stored, sent *T, cleared bool) *T {
+	if cleared {
+		return nil
+	}
+	if sent != nil {
+		return sent
+	}
+	return stored
+}
+
+func validateCouponWindow(startsAt, expiredAt *time.Time) error {
+	if startsAt != nil && expiredAt != nil && !startsAt.Before(*expiredAt) {
+		return errs.BadRequestError{Message: "starts_at must be before expired_at"}
+	}
+	return nil
+}
+
+func parseOptionalUint(value string) (*uint64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseOptionalBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(value)
+}
+
+func parseOptionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func derefInt(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
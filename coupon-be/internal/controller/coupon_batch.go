@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/repositories"
+	"coupon-be/internal/schema"
+	"coupon-be/internal/services"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
+	"time"
+)
+
+// couponBatchChunkSize is the number of codes inserted per transaction.
+const couponBatchChunkSize = 1000
+
+// couponBatchMaxStalls is how many chunks in a row may insert nothing, because
+// every generated code was taken, before a batch gives up.
+const couponBatchMaxStalls = 3
+
+// singleUse is the redemption limit of every generated code.
+var singleUse = 1
+
+type CouponBatchController interface {
+	CreateCouponBatch(ctx context.Context, parentCode string, req schema.CreateCouponBatchRequest) (model.CouponBatch, error)
+	GetCouponBatch(ctx context.Context, id uint64) (model.CouponBatch, error)
+	GetCouponBatchCodes(ctx context.Context, id uint64) ([]string, error)
+	ResumeCouponBatches(ctx context.Context)
+}
+
+type couponBatchController struct {
+	l  logger.Interface
+	cr repositories.CouponRepository
+	br repositories.CouponBatchRepository
+}
+
+func NewCouponBatchController(l logger.Interface, cr repositories.CouponRepository, br repositories.CouponBatchRepository) CouponBatchController {
+	return &couponBatchController{
+		l:  l,
+		cr: cr,
+		br: br,
+	}
+}
+
+// CreateCouponBatch records a batch and generates its codes in the
+// background. Poll the batch until it is completed to download the codes.
+func (c *couponBatchController) CreateCouponBatch(ctx context.Context, parentCode string, req schema.CreateCouponBatchRequest) (model.CouponBatch, error) {
+	parent, err := c.cr.GetCouponByID(ctx, parentCode)
+	if err != nil {
+		return model.CouponBatch{}, err
+	}
+	if parent.ParentCouponCode != nil {
+		return model.CouponBatch{}, errs.BadRequestError{Message: fmt.Sprintf("coupon %s was generated by a batch and cannot be used as a parent", parentCode)}
+	}
+	if parent.Status == model.CouponStatusArchived {
+		return model.CouponBatch{}, errs.BadRequestError{Message: fmt.Sprintf("coupon %s is archived", parentCode)}
+	}
+
+	template := services.CodeTemplate{Alphabet: services.DefaultCodeAlphabet, Length: 8}
+	if req.Prefix != nil {
+		template.Prefix = *req.Prefix
+	}
+	if req.Alphabet != nil {
+		template.Alphabet = *req.Alphabet
+	}
+	if req.Length != nil {
+		template.Length = *req.Length
+	}
+	if req.CheckDigit != nil {
+		template.CheckDigit = *req.CheckDigit
+	}
+	if err := template.Validate(req.Quantity); err != nil {
+		return model.CouponBatch{}, errs.BadRequestError{Message: err.Error()}
+	}
+
+	batch, err := c.br.CreateBatch(ctx, model.CouponBatch{
+		ParentCouponCode: parent.CouponCode,
+		Quantity:         req.Quantity,
+		Prefix:           template.Prefix,
+		Alphabet:         template.Alphabet,
+		Length:           template.Length,
+		CheckDigit:       template.CheckDigit,
+		Status:           model.CouponBatchStatusPending,
+	})
+	if err != nil {
+		c.l.Error("Failed to create coupon batch", "error", err, "parent_coupon_code", parentCode)
+		return model.CouponBatch{}, err
+	}
+	go c.generate(batch, parent, template)
+	return batch, nil
+}
+
+func (c *couponBatchController) GetCouponBatch(ctx context.Context, id uint64) (model.CouponBatch, error) {
+	return c.br.GetBatchByID(ctx, id)
+}
+
+// GetCouponBatchCodes returns the codes of a completed batch.
+func (c *couponBatchController) GetCouponBatchCodes(ctx context.Context, id uint64) ([]string, error) {
+	batch, err := c.br.GetBatchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != model.CouponBatchStatusCompleted {
+		return nil, errs.BadRequestError{Message: fmt.Sprintf("coupon batch %d is %s and cannot be downloaded yet", id, batch.Status)}
+	}
+	return c.br.GetBatchCouponCodes(ctx, id)
+}
+
+// ResumeCouponBatches carries on with the batches a previous run left pending
+// or running. Generation starts again from the batch's generated count, which
+// is updated together with every chunk of codes, so no code is generated
+// twice. Batches whose parent coupon is gone are failed. It assumes a single
+// instance generates batches.
+func (c *couponBatchController) ResumeCouponBatches(ctx context.Context) {
+	batches, err := c.br.GetUnfinishedBatches(ctx)
+	if err != nil {
+		c.l.Error("Failed to get unfinished coupon batches", "error", err)
+		return
+	}
+	for _, batch := range batches {
+		parent, err := c.cr.GetCouponByID(ctx, batch.ParentCouponCode)
+		if err != nil {
+			c.failBatch(ctx, batch.ID, fmt.Errorf("could not resume batch: %w", err))
+			continue
+		}
+		c.l.Info("Resuming coupon batch", "batch_id", batch.ID, "generated", batch.Generated, "quantity", batch.Quantity)
+		go c.generate(batch, parent, batchTemplate(batch))
+	}
+}
+
+// batchTemplate returns the code template a batch was created with.
+func batchTemplate(batch model.CouponBatch) services.CodeTemplate {
+	return services.CodeTemplate{
+		Prefix:     batch.Prefix,
+		Alphabet:   batch.Alphabet,
+		Length:     batch.Length,
+		CheckDigit: batch.CheckDigit,
+	}
+}
+
+// generate inserts the batch's missing codes chunk by chunk, so a failure
+// keeps the chunks already written and reports how far it got.
+func (c *couponBatchController) generate(batch model.CouponBatch, parent model.Coupon, template services.CodeTemplate) {
+	ctx := context.Background()
+	if err := c.br.UpdateBatch(ctx, batch.ID, map[string]any{"status": model.CouponBatchStatusRunning}); err != nil {
+		c.l.Error("Failed to start coupon batch", "error", err, "batch_id", batch.ID)
+		return
+	}
+
+	remaining, stalls := batch.Quantity-batch.Generated, 0
+	for remaining > 0 {
+		coupons, err := batchCoupons(parent, batch.ID, template, min(remaining, couponBatchChunkSize))
+		if err != nil {
+			c.failBatch(ctx, batch.ID, err)
+			return
+		}
+		inserted, err := c.br.InsertBatchCoupons(ctx, batch.ID, coupons)
+		if err != nil {
+			c.failBatch(ctx, batch.ID, err)
+			return
+		}
+		remaining -= inserted
+		if inserted > 0 {
+			stalls = 0
+			continue
+		}
+		if stalls++; stalls >= couponBatchMaxStalls {
+			c.failBatch(ctx, batch.ID, fmt.Errorf("could not generate unique codes, use a longer length or alphabet"))
+			return
+		}
+	}
+
+	err := c.br.UpdateBatch(ctx, batch.ID, map[string]any{
+		"status":       model.CouponBatchStatusCompleted,
+		"completed_at": time.Now(),
+	})
+	if err != nil {
+		c.l.Error("Failed to complete coupon batch", "error", err, "batch_id", batch.ID)
+		return
+	}
+	c.l.Info("Generated coupon batch", "batch_id", batch.ID, "quantity", batch.Quantity)
+}
+
+func (c *couponBatchController) failBatch(ctx context.Context, id uint64, cause error) {
+	c.l.Error("Failed to generate coupon batch", "error", cause, "batch_id", id)
+	message := cause.Error()
+	err := c.br.UpdateBatch(ctx, id, map[string]any{
+		"status": model.CouponBatchStatusFailed,
+		"error":  message,
+	})
+	if err != nil {
+		c.l.Error("Failed to record coupon batch failure", "error", err, "batch_id", id)
+	}
+}
+
+// batchCoupons generates size distinct single-use coupons copying the parent's
+// offer. Generated codes are always entered by hand, even if the parent is
+// applied automatically. They keep the parent's budget, which they all draw
+// from together with the parent rather than each having a copy of it.
+func batchCoupons(parent model.Coupon, batchID uint64, template services.CodeTemplate, size int) ([]model.Coupon, error) {
+	seen := make(map[string]bool, size)
+	coupons := make([]model.Coupon, 0, size)
+	for len(coupons) < size {
+		code, err := template.Generate()
+		if err != nil {
+			return nil, err
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		coupon := parent
+		coupon.CouponCode = code
+		coupon.Usage = model.CouponUsageManual
+		coupon.ParentCouponCode = &parent.CouponCode
+		coupon.BatchID = &batchID
+		coupon.MaxRedemptions = &singleUse
+		coupon.MaxRedemptionsPerCustomer = nil
+		coupon.CreatedAt = time.Time{}
+		coupon.UpdatedAt = time.Time{}
+		coupons = append(coupons, coupon)
+	}
+	return coupons, nil
+}
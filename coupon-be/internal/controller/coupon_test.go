@@ -0,0 +1,339 @@
+package controller
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/repositories"
+	"coupon-be/internal/schema"
+	"coupon-be/internal/services"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeCouponRepository keeps coupons in memory and applies updates the way
+// MySQL would: the columns in the update map are set, all others are kept.
+type fakeCouponRepository struct {
+	repositories.CouponRepository
+	coupons map[string]model.Coupon
+}
+
+func (f *fakeCouponRepository) GetCouponByID(_ context.Context, id string) (model.Coupon, error) {
+	coupon, ok := f.coupons[id]
+	if !ok {
+		return model.Coupon{}, errs.NotFoundError{Message: "Coupon with ID " + id + " not found"}
+	}
+	return coupon, nil
+}
+
+func (f *fakeCouponRepository) UpdateCoupon(ctx context.Context, id string, data map[string]any) (model.Coupon, error) {
+	coupon, err := f.GetCouponByID(ctx, id)
+	if err != nil {
+		return model.Coupon{}, err
+	}
+	columns, err := couponColumns(coupon)
+	if err != nil {
+		return model.Coupon{}, err
+	}
+	for column, value := range data {
+		columns[column] = value
+	}
+	encoded, err := json.Marshal(columns)
+	if err != nil {
+		return model.Coupon{}, err
+	}
+	var updated model.Coupon
+	if err := json.Unmarshal(encoded, &updated); err != nil {
+		return model.Coupon{}, err
+	}
+	f.coupons[id] = updated
+	return updated, nil
+}
+
+// couponColumns returns the coupon's columns keyed by name.
+func couponColumns(coupon model.Coupon) (map[string]any, error) {
+	encoded, err := json.Marshal(coupon)
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]any{}
+	if err := json.Unmarshal(encoded, &columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+func newCouponTestController(t *testing.T, coupons ...model.Coupon) (CouponController, *fakeCouponRepository) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rc.Close() })
+	cr := &fakeCouponRepository{coupons: map[string]model.Coupon{}}
+	for _, coupon := range coupons {
+		cr.coupons[coupon.CouponCode] = coupon
+	}
+	cs := services.NewCouponService(logger.New("test"), services.CouponServiceDeps{})
+	return NewCouponController(logger.New("test"), cs, cr, nil, rc), cr
+}
+
+func TestUpdateCouponKeepsOmittedFields(t *testing.T) {
+	title := "Renamed"
+	limit := 100
+	perCustomer := 2
+	group := "shipping"
+	tiers := model.CouponTiers{
+		{Threshold: money.NewFromInt(100000), Discount: money.NewFromInt(5)},
+		{Threshold: money.NewFromInt(500000), Discount: money.NewFromInt(10)},
+	}
+	base := model.Coupon{
+		CouponCode:  "UPDATE",
+		Title:       "Update Test Coupon",
+		Description: "Description for Update Test Coupon",
+		CouponType:  model.CouponTypeFixed,
+		Usage:       model.CouponUsageManual,
+		Status:      model.CouponStatusActive,
+		ExpiredAt:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		CouponValue: money.NewFromInt(10000),
+		Currency:    "VND",
+	}
+	tests := []struct {
+		name   string
+		stored func(coupon *model.Coupon)
+	}{
+		{
+			name: "TC17.1 title-only update keeps redemption limits",
+			stored: func(coupon *model.Coupon) {
+				coupon.MaxRedemptions = &limit
+				coupon.MaxRedemptionsPerCustomer = &perCustomer
+			},
+		},
+		{
+			name: "TC17.2 title-only update keeps stacking rules",
+			stored: func(coupon *model.Coupon) {
+				coupon.StackingGroup = &group
+				coupon.Exclusive = true
+				coupon.Priority = 5
+			},
+		},
+		{
+			name: "TC17.3 title-only update keeps tiers",
+			stored: func(coupon *model.Coupon) {
+				coupon.CouponType = model.CouponTypeTiered
+				coupon.Tiers = tiers
+			},
+		},
+		{
+			name: "TC17.6 title-only update keeps buy-X-get-Y rules",
+			stored: func(coupon *model.Coupon) {
+				coupon.CouponType = model.CouponTypeBuyXGetY
+				coupon.BuyXGetY = &model.CouponBuyXGetY{
+					Buy: model.CouponItemRule{Categories: []string{"shoes"}, Quantity: 2},
+					Get: model.CouponItemRule{Categories: []string{"socks"}, Quantity: 1},
+				}
+			},
+		},
+		{
+			name: "TC17.9 title-only update keeps targeting",
+			stored: func(coupon *model.Coupon) {
+				coupon.Targeting = &model.CouponTargeting{IncludeCategories: []string{"shoes"}, ExcludeSKUs: []string{"SKU-9"}}
+			},
+		},
+		{
+			name: "TC17.10 title-only update keeps customer restrictions",
+			stored: func(coupon *model.Coupon) {
+				segment := "vip"
+				coupon.AllowedCustomerIDs = model.StringList{"customer-1", "customer-2"}
+				coupon.CustomerSegment = &segment
+			},
+		},
+		{
+			name: "TC17.11 title-only update keeps validity windows and time zone",
+			stored: func(coupon *model.Coupon) {
+				zone := "Asia/Ho_Chi_Minh"
+				coupon.ValidityWindows = model.CouponWindows{{Weekdays: []string{"sat", "sun"}, Start: "10:00", End: "14:00"}}
+				coupon.TimeZone = &zone
+			},
+		},
+		{
+			name: "TC17.12 title-only update keeps channel and payment method restrictions",
+			stored: func(coupon *model.Coupon) {
+				coupon.Channels = model.StringList{"app"}
+				coupon.PaymentMethods = model.StringList{"wallet", "card"}
+			},
+		},
+		{
+			name: "TC17.13 title-only update keeps the campaign",
+			stored: func(coupon *model.Coupon) {
+				campaignID := uint64(3)
+				coupon.CampaignID = &campaignID
+			},
+		},
+		{
+			name: "TC17.14 title-only update keeps the budget",
+			stored: func(coupon *model.Coupon) {
+				budget := money.NewFromInt(5000000)
+				coupon.Budget = &budget
+				coupon.AllowPartialDiscount = true
+			},
+		},
+		{
+			name: "TC17.16 title-only update keeps the claim limit and claim validity",
+			stored: func(coupon *model.Coupon) {
+				claimLimit := 500
+				validityDays := 7
+				coupon.RequiresClaim = true
+				coupon.ClaimLimit = &claimLimit
+				coupon.ClaimValidityDays = &validityDays
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := base
+			tt.stored(&stored)
+			cc, cr := newCouponTestController(t, stored)
+			got, err := cc.UpdateCoupon(context.Background(), stored.CouponCode, schema.UpdateCouponRequest{Title: &title})
+			if err != nil {
+				t.Fatalf("UpdateCoupon(), test name: %s, error = %v", tt.name, err)
+			}
+			want := stored
+			want.Title = title
+			assertSameCoupon(t, cr.coupons[stored.CouponCode], want)
+			assertSameCoupon(t, got, want)
+		})
+	}
+}
+
+func TestUpdateCouponClearsListedFields(t *testing.T) {
+	limit := 100
+	stored := model.Coupon{
+		CouponCode:     "UPDATE",
+		Title:          "Update Test Coupon",
+		CouponType:     model.CouponTypeFixed,
+		Usage:          model.CouponUsageManual,
+		Status:         model.CouponStatusActive,
+		ExpiredAt:      time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		CouponValue:    money.NewFromInt(10000),
+		Currency:       "VND",
+		MaxRedemptions: &limit,
+	}
+	cc, _ := newCouponTestController(t, stored)
+
+	_, err := cc.UpdateCoupon(context.Background(), stored.CouponCode, schema.UpdateCouponRequest{MaxRedemptions: &limit, Clear: []string{"max_redemptions"}})
+	want := errs.BadRequestError{Message: "max_redemptions cannot be both set and cleared"}
+	if err == nil || err.Error() != want.Error() {
+		t.Fatalf("UpdateCoupon() setting and clearing a field, error = %v, wantErr %v", err, want)
+	}
+	got, err := cc.UpdateCoupon(context.Background(), stored.CouponCode, schema.UpdateCouponRequest{Clear: []string{"max_redemptions"}})
+	if err != nil {
+		t.Fatalf("UpdateCoupon(), error = %v", err)
+	}
+	if got.MaxRedemptions != nil {
+		t.Errorf("UpdateCoupon(), max_redemptions = %d, want null", *got.MaxRedemptions)
+	}
+}
+
+func TestUpdateCouponValidatesMergedCoupon(t *testing.T) {
+	value := money.NewFromInt(20000)
+	base := model.Coupon{
+		CouponCode:  "UPDATE",
+		Title:       "Update Test Coupon",
+		Usage:       model.CouponUsageManual,
+		Status:      model.CouponStatusActive,
+		ExpiredAt:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		CouponValue: money.NewFromInt(10000),
+		Currency:    "VND",
+	}
+	tiered := func(coupon *model.Coupon) {
+		coupon.CouponType = model.CouponTypeTiered
+		coupon.Tiers = model.CouponTiers{{Threshold: money.NewFromInt(100000), Discount: money.NewFromInt(5)}}
+	}
+	buyXGetY := func(coupon *model.Coupon) {
+		coupon.CouponType = model.CouponTypeBuyXGetY
+		coupon.BuyXGetY = &model.CouponBuyXGetY{
+			Buy: model.CouponItemRule{SKUs: []string{"SKU-1"}, Quantity: 2},
+			Get: model.CouponItemRule{SKUs: []string{"SKU-1"}, Quantity: 1},
+		}
+	}
+	tests := []struct {
+		name   string
+		stored func(coupon *model.Coupon)
+		req    schema.UpdateCouponRequest
+		err    error
+	}{
+		{
+			name:   "TC17.4 update of a tiered coupon without tiers checks the stored tiers",
+			stored: tiered,
+			req:    schema.UpdateCouponRequest{CouponValue: &value},
+		},
+		{
+			name:   "TC17.5 clearing the tiers of a tiered coupon",
+			stored: tiered,
+			req:    schema.UpdateCouponRequest{Clear: []string{"tiers"}},
+			err:    errs.BadRequestError{Message: "tiers are required for tiered coupons"},
+		},
+		{
+			name:   "TC17.7 update of a buy-X-get-Y coupon without rules checks the stored rules",
+			stored: buyXGetY,
+			req:    schema.UpdateCouponRequest{CouponValue: &value},
+		},
+		{
+			name:   "TC17.8 clearing the rules of a buy-X-get-Y coupon",
+			stored: buyXGetY,
+			req:    schema.UpdateCouponRequest{Clear: []string{"buy_x_get_y"}},
+			err:    errs.BadRequestError{Message: "buy_x_get_y is required for buy_x_get_y coupons"},
+		},
+		{
+			name: "TC17.15 update of a coupon with a budget without sending the budget keeps it",
+			stored: func(coupon *model.Coupon) {
+				coupon.CouponType = model.CouponTypeFixed
+				budget := money.NewFromInt(5000000)
+				coupon.Budget = &budget
+			},
+			req: schema.UpdateCouponRequest{CouponValue: &value},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := base
+			tt.stored(&stored)
+			cc, cr := newCouponTestController(t, stored)
+			_, err := cc.UpdateCoupon(context.Background(), stored.CouponCode, tt.req)
+			if (err == nil) != (tt.err == nil) || err != nil && err.Error() != tt.err.Error() {
+				t.Fatalf("UpdateCoupon(), test name: %s, error = %v, wantErr %v", tt.name, err, tt.err)
+			}
+			want := stored
+			if tt.req.CouponValue != nil && tt.err == nil {
+				want.CouponValue = *tt.req.CouponValue
+			}
+			assertSameCoupon(t, cr.coupons[stored.CouponCode], want)
+		})
+	}
+}
+
+// assertSameCoupon compares every column but updated_at.
+func assertSameCoupon(t *testing.T, got, want model.Coupon) {
+	t.Helper()
+	got.UpdatedAt, want.UpdatedAt = time.Time{}, time.Time{}
+	gotColumns, err := couponColumns(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantColumns, err := couponColumns(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for column, value := range wantColumns {
+		gotJSON, _ := json.Marshal(gotColumns[column])
+		wantJSON, _ := json.Marshal(value)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("%s = %s, want %s", column, gotJSON, wantJSON)
+		}
+	}
+}
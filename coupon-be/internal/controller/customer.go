@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"context"
+	"coupon-be/internal/repositories"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+)
+
+type CustomerController interface {
+	GetCustomerSegments(ctx context.Context, customerID string) (schema.CustomerSegmentsResponse, error)
+	UpdateCustomerSegments(ctx context.Context, customerID string, req schema.UpdateCustomerSegmentsRequest) (schema.CustomerSegmentsResponse, error)
+}
+
+type customerController struct {
+	l  logger.Interface
+	cu repositories.CustomerRepository
+}
+
+func NewCustomerController(l logger.Interface, cu repositories.CustomerRepository) CustomerController {
+	return &customerController{
+		l:  l,
+		cu: cu,
+	}
+}
+
+func (c *customerController) GetCustomerSegments(ctx context.Context, customerID string) (schema.CustomerSegmentsResponse, error) {
+	segments, err := c.cu.GetCustomerSegments(ctx, customerID)
+	if err != nil {
+		c.l.Error("Failed to get customer segments", "error", err, "customer_id", customerID)
+		return schema.CustomerSegmentsResponse{}, err
+	}
+	return schema.CustomerSegmentsResponse{CustomerID: customerID, Segments: segments}, nil
+}
+
+func (c *customerController) UpdateCustomerSegments(ctx context.Context, customerID string, req schema.UpdateCustomerSegmentsRequest) (schema.CustomerSegmentsResponse, error) {
+	segments, err := c.cu.SetCustomerSegments(ctx, customerID, req.Segments)
+	if err != nil {
+		c.l.Error("Failed to update customer segments", "error", err, "customer_id", customerID)
+		return schema.CustomerSegmentsResponse{}, err
+	}
+	return schema.CustomerSegmentsResponse{CustomerID: customerID, Segments: segments}, nil
+}
@@ -0,0 +1,370 @@
+package controller
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/repositories"
+	"coupon-be/internal/schema"
+	"coupon-be/internal/services"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// flashQueueKey holds claims taken from Redis stock that still have to be
+	// persisted; flashProcessingKey holds the ones being persisted, so they
+	// survive a restart in the middle of it.
+	flashQueueKey      = "flash:queue"
+	flashProcessingKey = "flash:processing"
+	flashPollTimeout   = 5 * time.Second
+	flashRetryDelay    = time.Second
+	// flashKeyGrace keeps a flash sale's keys around for a while after the
+	// coupon expires.
+	flashKeyGrace = 24 * time.Hour
+)
+
+// Results of flashClaimScript other than the remaining stock.
+const (
+	flashSoldOut        = -1
+	flashAlreadyClaimed = -2
+	flashNotInitialized = -3
+)
+
+// flashClaimScript takes one unit of stock for a customer and queues the
+// claim for persistence, all in one step so concurrent claims cannot oversell.
+// The claimants set holds every customer with a claim, queued or persisted,
+// and the pending counter the claims not persisted yet.
+//
+// KEYS: stock, claimants, pending, queue. ARGV: customer ID, claim payload.
+var flashClaimScript = redis.NewScript(`
+local stock = redis.call('GET', KEYS[1])
+if not stock then
+	return -3
+end
+if redis.call('SISMEMBER', KEYS[2], ARGV[1]) == 1 then
+	return -2
+end
+if tonumber(stock) <= 0 then
+	return -1
+end
+local remaining = redis.call('DECR', KEYS[1])
+redis.call('SADD', KEYS[2], ARGV[1])
+redis.call('INCR', KEYS[3])
+redis.call('LPUSH', KEYS[4], ARGV[2])
+return remaining
+`)
+
+// flashInitScript sets the stock of a flash sale from the claims persisted in
+// MySQL, unless another claim already did. Claims queued but not persisted
+// yet are still in the claimants set, so they are not sold twice.
+//
+// KEYS: stock, claimants, pending. ARGV: claim limit, TTL in seconds,
+// persisted claimants.
+var flashInitScript = redis.NewScript(`
+local stock = redis.call('GET', KEYS[1])
+if stock then
+	return tonumber(stock)
+end
+for i = 3, #ARGV do
+	redis.call('SADD', KEYS[2], ARGV[i])
+end
+local remaining = math.max(tonumber(ARGV[1]) - redis.call('SCARD', KEYS[2]), 0)
+redis.call('SET', KEYS[1], remaining, 'EX', ARGV[2])
+redis.call('EXPIRE', KEYS[2], ARGV[2])
+redis.call('SET', KEYS[3], 0, 'EX', ARGV[2], 'NX')
+return remaining
+`)
+
+// flashSoldOutScript drops a claim MySQL turned down because the coupon sold
+// out there, and empties the Redis stock to match.
+//
+// KEYS: stock, claimants. ARGV: customer ID.
+var flashSoldOutScript = redis.NewScript(`
+redis.call('SREM', KEYS[2], ARGV[1])
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('SET', KEYS[1], 0, 'KEEPTTL')
+end
+return 1
+`)
+
+// flashPersistedScript removes a claim from the processing list once MySQL
+// settled it, and counts it off the pending claims unless a previous attempt
+// already did.
+//
+// KEYS: processing, pending. ARGV: claim payload.
+var flashPersistedScript = redis.NewScript(`
+local removed = redis.call('LREM', KEYS[1], 1, ARGV[1])
+if removed > 0 and redis.call('EXISTS', KEYS[2]) == 1 then
+	redis.call('DECR', KEYS[2])
+end
+return removed
+`)
+
+type FlashClaimController interface {
+	ClaimCoupon(ctx context.Context, couponCode string, req schema.FlashClaimRequest) (schema.FlashClaimResponse, error)
+	GetFlashSale(ctx context.Context, couponCode string) (schema.FlashSaleResponse, error)
+	// Run persists queued claims until ctx is done. Claims left in flight by
+	// a previous run are queued again first.
+	Run(ctx context.Context)
+}
+
+type flashClaimController struct {
+	l     logger.Interface
+	cs    services.CouponService
+	cc    CouponController
+	wr    repositories.WalletRepository
+	redis *redis.Client
+	// pollTimeout bounds how long Run waits for a claim, and so how long it
+	// takes to notice it should stop.
+	pollTimeout time.Duration
+}
+
+// NewFlashClaimController claims limited coupons against stock kept in Redis.
+// Coupons are read through the coupon controller's cache, so a flash sale
+// does not read the coupon from MySQL on every claim.
+func NewFlashClaimController(l logger.Interface, cs services.CouponService, cc CouponController, wr repositories.WalletRepository, rc *redis.Client) FlashClaimController {
+	return &flashClaimController{
+		l:     l,
+		cs:    cs,
+		cc:    cc,
+		wr:    wr,
+		redis: rc,
+
+		pollTimeout: flashPollTimeout,
+	}
+}
+
+// flashClaim is a claim queued for persistence.
+type flashClaim struct {
+	CouponCode string    `json:"coupon_code"`
+	CustomerID string    `json:"customer_id"`
+	ClaimedAt  time.Time `json:"claimed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+func flashStockKey(couponCode string) string {
+	return "flash:" + couponCode + ":stock"
+}
+
+func flashClaimantsKey(couponCode string) string {
+	return "flash:" + couponCode + ":claimants"
+}
+
+func flashPendingKey(couponCode string) string {
+	return "flash:" + couponCode + ":pending"
+}
+
+func (c *flashClaimController) ClaimCoupon(ctx context.Context, couponCode string, req schema.FlashClaimRequest) (schema.FlashClaimResponse, error) {
+	coupon, err := c.cc.GetCouponByID(ctx, couponCode)
+	if err != nil {
+		return schema.FlashClaimResponse{}, err
+	}
+	if coupon.ClaimLimit == nil {
+		return schema.FlashClaimResponse{}, errs.BadRequestError{Message: fmt.Sprintf("coupon %s has no claim limit and is claimed through the customer wallet", couponCode)}
+	}
+	now := time.Now()
+	if err := c.cs.ValidateClaim(ctx, coupon, req.CustomerID, now); err != nil {
+		return schema.FlashClaimResponse{}, errs.BadRequestError{Message: err.Error()}
+	}
+
+	wallet := newWalletCoupon(coupon, req.CustomerID, now)
+	payload, err := json.Marshal(flashClaim{
+		CouponCode: wallet.CouponCode,
+		CustomerID: wallet.CustomerID,
+		ClaimedAt:  wallet.ClaimedAt,
+		ExpiresAt:  wallet.ExpiresAt,
+	})
+	if err != nil {
+		return schema.FlashClaimResponse{}, err
+	}
+	keys := []string{flashStockKey(couponCode), flashClaimantsKey(couponCode), flashPendingKey(couponCode), flashQueueKey}
+	remaining, err := flashClaimScript.Run(ctx, c.redis, keys, req.CustomerID, payload).Int64()
+	if err == nil && remaining == flashNotInitialized {
+		if _, err = c.initStock(ctx, coupon); err == nil {
+			remaining, err = flashClaimScript.Run(ctx, c.redis, keys, req.CustomerID, payload).Int64()
+		}
+	}
+	if err != nil {
+		c.l.Error("Failed to claim flash sale coupon", "error", err, "coupon_code", couponCode, "customer_id", req.CustomerID)
+		return schema.FlashClaimResponse{}, err
+	}
+
+	switch remaining {
+	case flashSoldOut:
+		return schema.FlashClaimResponse{}, errs.BadRequestError{Message: fmt.Sprintf("coupon %s is sold out", couponCode)}
+	case flashAlreadyClaimed:
+		return schema.FlashClaimResponse{}, errs.BadRequestError{Message: fmt.Sprintf("Customer %s already claimed coupon %s", req.CustomerID, couponCode)}
+	case flashNotInitialized:
+		return schema.FlashClaimResponse{}, fmt.Errorf("stock of coupon %s is not initialized", couponCode)
+	}
+	return schema.FlashClaimResponse{
+		CouponCode: couponCode,
+		CustomerID: req.CustomerID,
+		Remaining:  remaining,
+		ClaimedAt:  wallet.ClaimedAt,
+		ExpiresAt:  wallet.ExpiresAt,
+	}, nil
+}
+
+// GetFlashSale reports the stock of a flash sale from Redis. Pending claims
+// are taken from Redis stock but not persisted yet.
+func (c *flashClaimController) GetFlashSale(ctx context.Context, couponCode string) (schema.FlashSaleResponse, error) {
+	coupon, err := c.cc.GetCouponByID(ctx, couponCode)
+	if err != nil {
+		return schema.FlashSaleResponse{}, err
+	}
+	if coupon.ClaimLimit == nil {
+		return schema.FlashSaleResponse{}, errs.BadRequestError{Message: fmt.Sprintf("coupon %s has no claim limit", couponCode)}
+	}
+	remaining, err := c.initStock(ctx, coupon)
+	if err != nil {
+		return schema.FlashSaleResponse{}, err
+	}
+	var claimants *redis.IntCmd
+	var pending *redis.StringCmd
+	_, err = c.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		claimants = pipe.SCard(ctx, flashClaimantsKey(couponCode))
+		pending = pipe.Get(ctx, flashPendingKey(couponCode))
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		c.l.Error("Failed to count flash sale claimants", "error", err, "coupon_code", couponCode)
+		return schema.FlashSaleResponse{}, err
+	}
+	queued, err := pending.Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return schema.FlashSaleResponse{}, err
+	}
+	queued = min(max(queued, 0), claimants.Val())
+	return schema.FlashSaleResponse{
+		CouponCode: couponCode,
+		ClaimLimit: *coupon.ClaimLimit,
+		Remaining:  remaining,
+		Claimed:    claimants.Val() - queued,
+		Pending:    queued,
+	}, nil
+}
+
+// initStock reconciles the Redis stock of a flash sale with the claims
+// persisted in MySQL if Redis has none, and returns the remaining stock.
+// MySQL is only read while the stock key is missing.
+func (c *flashClaimController) initStock(ctx context.Context, coupon model.Coupon) (int64, error) {
+	stock, err := c.redis.Get(ctx, flashStockKey(coupon.CouponCode)).Int64()
+	if err == nil {
+		return stock, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		c.l.Error("Failed to get flash sale stock", "error", err, "coupon_code", coupon.CouponCode)
+		return 0, err
+	}
+	claimants, err := c.wr.GetCouponClaimants(ctx, coupon.CouponCode)
+	if err != nil {
+		c.l.Error("Failed to get coupon claimants", "error", err, "coupon_code", coupon.CouponCode)
+		return 0, err
+	}
+	ttl := time.Until(coupon.ExpiredAt) + flashKeyGrace
+	args := make([]any, 0, len(claimants)+2)
+	args = append(args, *coupon.ClaimLimit, max(int64(ttl.Seconds()), 1))
+	for _, customerID := range claimants {
+		args = append(args, customerID)
+	}
+	keys := []string{flashStockKey(coupon.CouponCode), flashClaimantsKey(coupon.CouponCode), flashPendingKey(coupon.CouponCode)}
+	remaining, err := flashInitScript.Run(ctx, c.redis, keys, args...).Int64()
+	if err != nil {
+		c.l.Error("Failed to initialize flash sale stock", "error", err, "coupon_code", coupon.CouponCode)
+		return 0, err
+	}
+	return remaining, nil
+}
+
+func (c *flashClaimController) Run(ctx context.Context) {
+	c.requeueProcessing(ctx)
+	for {
+		payload, err := c.redis.BLMove(ctx, flashQueueKey, flashProcessingKey, "RIGHT", "LEFT", c.pollTimeout).Result()
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			c.l.Error("Failed to take flash sale claim", "error", err)
+			c.wait(ctx, flashRetryDelay)
+			continue
+		}
+		if err := c.persistClaim(ctx, payload); err != nil {
+			c.l.Error("Failed to persist flash sale claim", "error", err, "claim", payload)
+			c.requeue(ctx, payload)
+			c.wait(ctx, flashRetryDelay)
+		}
+	}
+}
+
+// persistClaim saves a claim taken from Redis stock to MySQL and removes it
+// from the processing list and the pending claims. It returns an error, leaving the claim in
+// processing, only if the claim should be retried.
+func (c *flashClaimController) persistClaim(ctx context.Context, payload string) error {
+	var claim flashClaim
+	if err := json.Unmarshal([]byte(payload), &claim); err != nil {
+		c.l.Error("Dropping malformed flash sale claim", "error", err, "claim", payload)
+		return c.redis.LRem(ctx, flashProcessingKey, 1, payload).Err()
+	}
+	claimed, err := c.wr.ClaimLimitedCoupon(ctx, model.WalletCoupon{
+		CustomerID: claim.CustomerID,
+		CouponCode: claim.CouponCode,
+		ClaimedAt:  claim.ClaimedAt,
+		ExpiresAt:  claim.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		c.l.Info("Flash sale claim turned down by MySQL", "coupon_code", claim.CouponCode, "customer_id", claim.CustomerID)
+		keys := []string{flashStockKey(claim.CouponCode), flashClaimantsKey(claim.CouponCode)}
+		if err := flashSoldOutScript.Run(ctx, c.redis, keys, claim.CustomerID).Err(); err != nil {
+			return err
+		}
+	}
+	keys := []string{flashProcessingKey, flashPendingKey(claim.CouponCode)}
+	return flashPersistedScript.Run(ctx, c.redis, keys, payload).Err()
+}
+
+// requeue moves a claim that failed to persist back to the end of the queue.
+func (c *flashClaimController) requeue(ctx context.Context, payload string) {
+	_, err := c.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, flashProcessingKey, 1, payload)
+		pipe.LPush(ctx, flashQueueKey, payload)
+		return nil
+	})
+	if err != nil {
+		c.l.Error("Failed to requeue flash sale claim", "error", err, "claim", payload)
+	}
+}
+
+// requeueProcessing queues again the claims a previous run stopped in the
+// middle of persisting. Persisting a claim twice is harmless.
+func (c *flashClaimController) requeueProcessing(ctx context.Context) {
+	for {
+		_, err := c.redis.LMove(ctx, flashProcessingKey, flashQueueKey, "RIGHT", "RIGHT").Result()
+		if errors.Is(err, redis.Nil) {
+			return
+		}
+		if err != nil {
+			c.l.Error("Failed to requeue flash sale claims", "error", err)
+			return
+		}
+	}
+}
+
+func (c *flashClaimController) wait(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
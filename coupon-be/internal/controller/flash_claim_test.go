@@ -0,0 +1,286 @@
+package controller
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/repositories"
+	"coupon-be/internal/schema"
+	"coupon-be/internal/services"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeFlashCoupons serves a single coupon, as the coupon controller's cache
+// would.
+type fakeFlashCoupons struct {
+	CouponController
+	coupon model.Coupon
+}
+
+func (f *fakeFlashCoupons) GetCouponByID(_ context.Context, id string) (model.Coupon, error) {
+	if id != f.coupon.CouponCode {
+		return model.Coupon{}, errs.NotFoundError{Message: "Coupon not found"}
+	}
+	return f.coupon, nil
+}
+
+// fakeFlashWallets stands in for MySQL: it keeps the persisted claims and
+// turns claims down past the claim limit, or all of them if refuse is set.
+type fakeFlashWallets struct {
+	repositories.WalletRepository
+	mu             sync.Mutex
+	limit          int
+	refuse         bool
+	claims         map[string]model.WalletCoupon
+	claimantsReads int
+}
+
+func newFakeFlashWallets(limit int, persisted ...string) *fakeFlashWallets {
+	f := &fakeFlashWallets{limit: limit, claims: map[string]model.WalletCoupon{}}
+	for _, customerID := range persisted {
+		f.claims[customerID] = model.WalletCoupon{CustomerID: customerID}
+	}
+	return f
+}
+
+func (f *fakeFlashWallets) ClaimLimitedCoupon(_ context.Context, wallet model.WalletCoupon) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.claims[wallet.CustomerID]; ok {
+		return true, nil
+	}
+	if f.refuse || len(f.claims) >= f.limit {
+		return false, nil
+	}
+	f.claims[wallet.CustomerID] = wallet
+	return true, nil
+}
+
+func (f *fakeFlashWallets) GetCouponClaimants(_ context.Context, _ string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.claimantsReads++
+	claimants := []string{}
+	for customerID := range f.claims {
+		claimants = append(claimants, customerID)
+	}
+	return claimants, nil
+}
+
+func (f *fakeFlashWallets) persisted() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.claims)
+}
+
+func newFlashTestController(t *testing.T, limit int, wr *fakeFlashWallets) (*flashClaimController, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rc := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rc.Close() })
+	coupon := model.Coupon{
+		CouponCode: "FLASH",
+		CouponType: model.CouponTypeFixed,
+		Status:     model.CouponStatusActive,
+		ExpiredAt:  time.Now().Add(time.Hour),
+		ClaimLimit: &limit,
+	}
+	cs := services.NewCouponService(logger.New("test"), services.CouponServiceDeps{})
+	fc := NewFlashClaimController(logger.New("test"), cs, &fakeFlashCoupons{coupon: coupon}, wr, rc)
+	impl := fc.(*flashClaimController)
+	impl.pollTimeout = 50 * time.Millisecond
+	return impl, mr
+}
+
+// runFlashWorker runs the worker until the queue and processing list are
+// empty.
+func runFlashWorker(t *testing.T, fc *flashClaimController, mr *miniredis.Miniredis) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		fc.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		queued, _ := mr.List(flashQueueKey)
+		processing, _ := mr.List(flashProcessingKey)
+		if len(queued) == 0 && len(processing) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("flash sale claims were not persisted in time")
+}
+
+func getFlashSale(t *testing.T, fc *flashClaimController) schema.FlashSaleResponse {
+	t.Helper()
+	sale, err := fc.GetFlashSale(context.Background(), "FLASH")
+	if err != nil {
+		t.Fatalf("GetFlashSale(), error = %v", err)
+	}
+	return sale
+}
+
+func TestFlashClaimConcurrentClaimsAtStockLimit(t *testing.T) {
+	wr := newFakeFlashWallets(5)
+	fc, mr := newFlashTestController(t, 5, wr)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	claimed, soldOut := 0, 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(customerID string) {
+			defer wg.Done()
+			_, err := fc.ClaimCoupon(context.Background(), "FLASH", schema.FlashClaimRequest{CustomerID: customerID})
+			mu.Lock()
+			defer mu.Unlock()
+			switch err.(type) {
+			case nil:
+				claimed++
+			case errs.BadRequestError:
+				soldOut++
+			default:
+				t.Errorf("ClaimCoupon(), customer %s, error = %v", customerID, err)
+			}
+		}(fmt.Sprintf("customer-%d", i))
+	}
+	wg.Wait()
+	if claimed != 5 || soldOut != 45 {
+		t.Fatalf("ClaimCoupon(), claimed = %d, sold out = %d, want 5 and 45", claimed, soldOut)
+	}
+	if sale := getFlashSale(t, fc); sale.Remaining != 0 || sale.Claimed != 0 || sale.Pending != 5 {
+		t.Errorf("GetFlashSale() before persisting = %+v, want 0 remaining, 0 claimed, 5 pending", sale)
+	}
+
+	runFlashWorker(t, fc, mr)
+	if got := wr.persisted(); got != 5 {
+		t.Errorf("persisted claims = %d, want 5", got)
+	}
+	if sale := getFlashSale(t, fc); sale.Remaining != 0 || sale.Claimed != 5 || sale.Pending != 0 {
+		t.Errorf("GetFlashSale() after persisting = %+v, want 0 remaining, 5 claimed, 0 pending", sale)
+	}
+}
+
+func TestFlashClaimDuplicateClaimant(t *testing.T) {
+	wr := newFakeFlashWallets(3)
+	fc, mr := newFlashTestController(t, 3, wr)
+	req := schema.FlashClaimRequest{CustomerID: "customer-1"}
+
+	resp, err := fc.ClaimCoupon(context.Background(), "FLASH", req)
+	if err != nil || resp.Remaining != 2 {
+		t.Fatalf("ClaimCoupon(), remaining = %d, error = %v, want 2 and no error", resp.Remaining, err)
+	}
+	_, err = fc.ClaimCoupon(context.Background(), "FLASH", req)
+	want := errs.BadRequestError{Message: "Customer customer-1 already claimed coupon FLASH"}
+	if err == nil || err.Error() != want.Error() {
+		t.Fatalf("ClaimCoupon() again before persisting, error = %v, wantErr %v", err, want)
+	}
+
+	runFlashWorker(t, fc, mr)
+	_, err = fc.ClaimCoupon(context.Background(), "FLASH", req)
+	if err == nil || err.Error() != want.Error() {
+		t.Fatalf("ClaimCoupon() again after persisting, error = %v, wantErr %v", err, want)
+	}
+	if sale := getFlashSale(t, fc); sale.Remaining != 2 || sale.Claimed != 1 || sale.Pending != 0 {
+		t.Errorf("GetFlashSale() = %+v, want 2 remaining, 1 claimed, 0 pending", sale)
+	}
+}
+
+func TestFlashClaimRefusedByMySQL(t *testing.T) {
+	wr := newFakeFlashWallets(3)
+	fc, mr := newFlashTestController(t, 3, wr)
+	if _, err := fc.ClaimCoupon(context.Background(), "FLASH", schema.FlashClaimRequest{CustomerID: "customer-1"}); err != nil {
+		t.Fatalf("ClaimCoupon(), error = %v", err)
+	}
+
+	// The coupon sold out in MySQL, e.g. through claims Redis lost track of.
+	wr.mu.Lock()
+	wr.refuse = true
+	wr.mu.Unlock()
+	runFlashWorker(t, fc, mr)
+
+	if got := wr.persisted(); got != 0 {
+		t.Errorf("persisted claims = %d, want 0", got)
+	}
+	if ok, _ := mr.SIsMember(flashClaimantsKey("FLASH"), "customer-1"); ok {
+		t.Errorf("customer-1 is still a claimant after MySQL turned the claim down")
+	}
+	if sale := getFlashSale(t, fc); sale.Remaining != 0 || sale.Claimed != 0 || sale.Pending != 0 {
+		t.Errorf("GetFlashSale() = %+v, want 0 remaining, 0 claimed, 0 pending", sale)
+	}
+	_, err := fc.ClaimCoupon(context.Background(), "FLASH", schema.FlashClaimRequest{CustomerID: "customer-2"})
+	want := errs.BadRequestError{Message: "coupon FLASH is sold out"}
+	if err == nil || err.Error() != want.Error() {
+		t.Errorf("ClaimCoupon() after sell-out, error = %v, wantErr %v", err, want)
+	}
+}
+
+func TestFlashClaimRequeueProcessingAfterCrash(t *testing.T) {
+	wr := newFakeFlashWallets(3)
+	fc, mr := newFlashTestController(t, 3, wr)
+	for _, customerID := range []string{"customer-1", "customer-2"} {
+		if _, err := fc.ClaimCoupon(context.Background(), "FLASH", schema.FlashClaimRequest{CustomerID: customerID}); err != nil {
+			t.Fatalf("ClaimCoupon(), customer %s, error = %v", customerID, err)
+		}
+	}
+	// A previous run took both claims and crashed before persisting them;
+	// customer-1's claim even reached MySQL.
+	for range 2 {
+		if _, err := fc.redis.LMove(context.Background(), flashQueueKey, flashProcessingKey, "RIGHT", "LEFT").Result(); err != nil {
+			t.Fatalf("LMove(), error = %v", err)
+		}
+	}
+	wr.claims["customer-1"] = model.WalletCoupon{CustomerID: "customer-1"}
+
+	runFlashWorker(t, fc, mr)
+
+	if got := wr.persisted(); got != 2 {
+		t.Errorf("persisted claims = %d, want 2", got)
+	}
+	if sale := getFlashSale(t, fc); sale.Remaining != 1 || sale.Claimed != 2 || sale.Pending != 0 {
+		t.Errorf("GetFlashSale() = %+v, want 1 remaining, 2 claimed, 0 pending", sale)
+	}
+}
+
+func TestGetFlashSaleReadsMySQLOnlyWithoutStock(t *testing.T) {
+	wr := newFakeFlashWallets(3, "customer-1", "customer-2")
+	fc, mr := newFlashTestController(t, 3, wr)
+
+	for range 3 {
+		if sale := getFlashSale(t, fc); sale.Remaining != 1 || sale.Claimed != 2 || sale.Pending != 0 {
+			t.Errorf("GetFlashSale() = %+v, want 1 remaining, 2 claimed, 0 pending", sale)
+		}
+	}
+	if wr.claimantsReads != 1 {
+		t.Errorf("GetCouponClaimants() called %d times, want 1", wr.claimantsReads)
+	}
+
+	// Losing the Redis keys rebuilds the stock from MySQL once more.
+	mr.FlushAll()
+	getFlashSale(t, fc)
+	if wr.claimantsReads != 2 {
+		t.Errorf("GetCouponClaimants() called %d times after losing the stock, want 2", wr.claimantsReads)
+	}
+	payload, _ := json.Marshal(flashClaim{CouponCode: "FLASH", CustomerID: "customer-1"})
+	if err := fc.persistClaim(context.Background(), string(payload)); err != nil {
+		t.Fatalf("persistClaim() of an already persisted claim, error = %v", err)
+	}
+	if sale := getFlashSale(t, fc); sale.Claimed != 2 || sale.Pending != 0 {
+		t.Errorf("GetFlashSale() after persisting a claim twice = %+v, want 2 claimed, 0 pending", sale)
+	}
+}
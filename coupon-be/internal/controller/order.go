@@ -8,82 +8,254 @@ import (
 	"coupon-be/internal/services"
 	"coupon-be/pkg/logger"
 	"coupon-be/pkg/utils/errs"
+	"fmt"
 )
 
 type OrderController interface {
 	CreateMockOrder(ctx context.Context, req schema.CreateMockOrderRequest) (schema.CreateMockOrderResponse, error)
+	CreateOrder(ctx context.Context, req schema.CreateMockOrderRequest) (schema.OrderResponse, error)
+	GetOrderByID(ctx context.Context, id uint64) (model.Order, error)
 }
 
 type orderController struct {
 	l  logger.Interface
 	cr repositories.CouponRepository
+	or repositories.OrderRepository
 	cs services.CouponService
 }
 
-func NewOrderController(l logger.Interface, cr repositories.CouponRepository, cs services.CouponService) OrderController {
+func NewOrderController(l logger.Interface, cr repositories.CouponRepository, or repositories.OrderRepository, cs services.CouponService) OrderController {
 	return &orderController{
 		l:  l,
 		cr: cr,
+		or: or,
 		cs: cs,
 	}
 }
 
+// orderPricing is the outcome of applying coupons to an order.
+type orderPricing struct {
+	applied     []services.AppliedCoupon
+	amounts     services.OrderAmounts
+	autoApplied bool
+	reason      string
+}
+
 func (c *orderController) CreateMockOrder(ctx context.Context, req schema.CreateMockOrderRequest) (schema.CreateMockOrderResponse, error) {
-	var coupon model.Coupon
-	var totalCost float64 = req.Cost
-	if req.CouponCode != nil {
-		var err error
-		coupon, err = c.getAndValidateCoupon(ctx, req)
-		if err != nil {
-			return schema.CreateMockOrderResponse{}, err
+	req, err := normalizeOrderRequest(req)
+	if err != nil {
+		return schema.CreateMockOrderResponse{}, err
+	}
+	pricing, err := c.priceOrder(ctx, req)
+	if err != nil {
+		return schema.CreateMockOrderResponse{}, err
+	}
+	response := schema.CreateMockOrderResponse{
+		Cost:                   req.Cost,
+		Subtotal:               req.Cost,
+		ShippingFee:            req.ShippingFee,
+		DiscountAmount:         req.Cost.Sub(pricing.amounts.Subtotal),
+		ShippingDiscountAmount: req.ShippingFee.Sub(pricing.amounts.ShippingFee),
+		Currency:               req.OrderCurrency(),
+		CreatedAt:              req.CreatedAt,
+		CouponCode:             nil,
+		TotalAmount:            pricing.amounts.Total(),
+		Items:                  schema.ToOrderItemResponses(orderItems(pricing.amounts)),
+		Coupons:                make([]schema.AppliedCouponResponse, len(pricing.applied)),
+		AutoApplied:            pricing.autoApplied,
+		Reason:                 pricing.reason,
+	}
+	for i, applied := range pricing.applied {
+		response.Coupons[i] = schema.AppliedCouponResponse{
+			CouponCode:     applied.Coupon.CouponCode,
+			DiscountAmount: applied.DiscountAmount,
+			TotalAmount:    applied.TotalAmount,
+			Tier:           schema.ToCouponTierResponse(applied.Coupon, applied.Tier),
+			Coupon:         schema.ToCouponResponse(applied.Coupon),
 		}
-		totalCost, err = c.cs.CalculateAmount(ctx, &coupon, req.Cost)
-		if err != nil {
-			c.l.Error("Failed to calculate total amount", "error", err)
-			return schema.CreateMockOrderResponse{}, errs.BadRequestError{
-				Message: "Failed to calculate total amount",
-			}
+	}
+	// coupon_code and coupon keep describing the first coupon applied for
+	// clients that only support a single coupon.
+	if len(pricing.applied) > 0 {
+		response.CouponCode = &response.Coupons[0].CouponCode
+		response.Coupon = &response.Coupons[0].Coupon
+	}
+	return response, nil
+}
+
+func (c *orderController) CreateOrder(ctx context.Context, req schema.CreateMockOrderRequest) (schema.OrderResponse, error) {
+	req, err := normalizeOrderRequest(req)
+	if err != nil {
+		return schema.OrderResponse{}, err
+	}
+	pricing, err := c.priceOrder(ctx, req)
+	if err != nil {
+		return schema.OrderResponse{}, err
+	}
+	order := model.Order{
+		CustomerID:             req.CustomerID,
+		Channel:                req.Channel,
+		PaymentMethod:          req.PaymentMethod,
+		Cost:                   req.Cost,
+		ShippingFee:            req.ShippingFee,
+		DiscountAmount:         req.Cost.Sub(pricing.amounts.Subtotal),
+		ShippingDiscountAmount: req.ShippingFee.Sub(pricing.amounts.ShippingFee),
+		TotalAmount:            pricing.amounts.Total(),
+		Currency:               req.OrderCurrency(),
+		Items:                  orderItems(pricing.amounts),
+		Redemptions:            make([]model.CouponRedemption, len(pricing.applied)),
+		CreatedAt:              req.CreatedAt,
+	}
+	for i, applied := range pricing.applied {
+		order.Redemptions[i] = model.CouponRedemption{
+			CouponCode:     applied.Coupon.CouponCode,
+			DiscountAmount: applied.DiscountAmount,
+			AutoApplied:    pricing.autoApplied,
+			CreatedAt:      req.CreatedAt,
 		}
-		return schema.CreateMockOrderResponse{
-			Cost:        req.Cost,
-			CreatedAt:   req.CreatedAt,
-			CouponCode:  req.CouponCode,
-			TotalAmount: totalCost,
-			Coupon: &schema.CouponResponse{
-				CouponCode:  coupon.CouponCode,
-				Title:       coupon.Title,
-				Description: coupon.Description,
-				CouponType:  coupon.CouponType,
-				CouponValue: coupon.CouponValue,
-				ExpiredAt:   coupon.ExpiredAt,
-				Usage:       coupon.Usage,
-				CreatedAt:   coupon.CreatedAt,
-				UpdatedAt:   coupon.UpdatedAt,
-			},
-		}, nil
 	}
-	return schema.CreateMockOrderResponse{
-		Cost:        req.Cost,
-		CreatedAt:   req.CreatedAt,
-		CouponCode:  nil,
-		TotalAmount: totalCost,
-	}, nil
+	order, err = c.or.CreateOrder(ctx, order)
+	if err != nil {
+		c.l.Error("Failed to create order", "error", err)
+		return schema.OrderResponse{}, err
+	}
+	response := schema.ToOrderResponse(order)
+	response.Reason = pricing.reason
+	return response, nil
 }
 
-func (c *orderController) getAndValidateCoupon(ctx context.Context, req schema.CreateMockOrderRequest) (model.Coupon, error) {
-	coupon, err := c.cr.GetCouponByID(ctx, *req.CouponCode)
+func (c *orderController) GetOrderByID(ctx context.Context, id uint64) (model.Order, error) {
+	order, err := c.or.GetOrderByID(ctx, id)
 	if err != nil {
-		c.l.Error("Failed to get coupon by code", "coupon_code", *req.CouponCode, "error", err)
-		return model.Coupon{}, errs.BadRequestError{
-			Message: "Coupon not found",
+		c.l.Error("Failed to get order by ID", "error", err, "id", id)
+		return model.Order{}, err
+	}
+	return order, nil
+}
+
+// priceOrder validates the requested coupons and applies them to the order.
+// Orders without coupon codes get the best eligible auto coupon instead.
+func (c *orderController) priceOrder(ctx context.Context, req schema.CreateMockOrderRequest) (orderPricing, error) {
+	codes := requestedCouponCodes(req)
+	if len(codes) == 0 {
+		return c.selectAutoCoupon(ctx, req)
+	}
+	coupons := make([]model.Coupon, 0, len(codes))
+	for _, code := range codes {
+		coupon, err := c.getCoupon(ctx, code)
+		if err != nil {
+			return orderPricing{}, err
 		}
+		coupons = append(coupons, coupon)
 	}
-	isValid, err := c.cs.ValidateCoupon(ctx, coupon, req)
-	if err != nil || !isValid {
+	applied, amounts, err := c.cs.ApplyCoupons(ctx, coupons, req)
+	if err != nil {
 		c.l.Error("Coupon validation failed", "error", err)
-		return model.Coupon{}, errs.BadRequestError{
+		return orderPricing{}, errs.BadRequestError{
 			Message: err.Error(),
 		}
 	}
+	return orderPricing{applied: applied, amounts: amounts}, nil
+}
+
+func (c *orderController) selectAutoCoupon(ctx context.Context, req schema.CreateMockOrderRequest) (orderPricing, error) {
+	coupons, err := c.cr.GetActiveAutoCoupons(ctx, req.CreatedAt)
+	if err != nil {
+		c.l.Error("Failed to get auto coupons", "error", err)
+		return orderPricing{}, err
+	}
+	selection, err := c.cs.SelectAutoCoupon(ctx, coupons, req)
+	if err != nil {
+		c.l.Error("Failed to select auto coupon", "error", err)
+		return orderPricing{}, err
+	}
+	if selection == nil {
+		return orderPricing{amounts: services.OrderAmounts{Subtotal: req.Cost, ShippingFee: req.ShippingFee}}, nil
+	}
+	return orderPricing{
+		applied: []services.AppliedCoupon{
+			{
+				Coupon:         selection.Coupon,
+				DiscountAmount: req.OrderTotal().Sub(selection.TotalAmount),
+				TotalAmount:    selection.TotalAmount,
+				Tier:           selection.Tier,
+			},
+		},
+		amounts:     selection.Amounts,
+		autoApplied: true,
+		reason:      selection.Reason,
+	}, nil
+}
+
+func (c *orderController) getCoupon(ctx context.Context, code string) (model.Coupon, error) {
+	coupon, err := c.cr.GetCouponByID(ctx, code)
+	if err != nil {
+		c.l.Error("Failed to get coupon by code", "coupon_code", code, "error", err)
+		return model.Coupon{}, errs.BadRequestError{
+			Message: "Coupon " + code + " not found",
+		}
+	}
 	return coupon, nil
 }
+
+// normalizeOrderRequest fills in the cost of orders placed with line items,
+// rejecting a cost that does not match them.
+func normalizeOrderRequest(req schema.CreateMockOrderRequest) (schema.CreateMockOrderRequest, error) {
+	if err := validateOrderAmounts(req); err != nil {
+		return req, err
+	}
+	if len(req.Items) == 0 {
+		return req, nil
+	}
+	itemsTotal := req.ItemsTotal()
+	if !req.Cost.IsZero() && !req.Cost.Equal(itemsTotal) {
+		return req, errs.BadRequestError{
+			Message: fmt.Sprintf("cost %s does not match the sum of the items %s", req.Cost.StringFixed(), itemsTotal.StringFixed()),
+		}
+	}
+	req.Cost = itemsTotal
+	return req, nil
+}
+
+// validateOrderAmounts rejects prices with more decimals than the order's
+// currency, which could not be charged or stored as given.
+func validateOrderAmounts(req schema.CreateMockOrderRequest) error {
+	currency := req.OrderCurrency()
+	if !req.Cost.FitsMinorUnit(currency) {
+		return errs.BadRequestError{Message: fmt.Sprintf("cost %s has more decimals than %s allows", req.Cost, currency)}
+	}
+	if !req.ShippingFee.FitsMinorUnit(currency) {
+		return errs.BadRequestError{Message: fmt.Sprintf("shipping_fee %s has more decimals than %s allows", req.ShippingFee, currency)}
+	}
+	for _, item := range req.Items {
+		if !item.UnitPrice.FitsMinorUnit(currency) {
+			return errs.BadRequestError{Message: fmt.Sprintf("unit_price %s of %s has more decimals than %s allows", item.UnitPrice, item.SKU, currency)}
+		}
+	}
+	return nil
+}
+
+// orderItems turns the priced lines of an order into order items.
+func orderItems(amounts services.OrderAmounts) []model.OrderItem {
+	items := make([]model.OrderItem, len(amounts.Lines))
+	for i, line := range amounts.Lines {
+		items[i] = model.OrderItem{
+			SKU:            line.Item.SKU,
+			Category:       line.Item.Category,
+			Quantity:       line.Item.Quantity,
+			UnitPrice:      line.Item.UnitPrice,
+			DiscountAmount: line.DiscountAmount(),
+			TotalAmount:    line.Amount,
+		}
+	}
+	return items
+}
+
+// requestedCouponCodes merges the single coupon_code field into coupon_codes.
+func requestedCouponCodes(req schema.CreateMockOrderRequest) []string {
+	codes := make([]string, 0, len(req.CouponCodes)+1)
+	if req.CouponCode != nil {
+		codes = append(codes, *req.CouponCode)
+	}
+	return append(codes, req.CouponCodes...)
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/repositories"
+	"coupon-be/internal/schema"
+	"coupon-be/internal/services"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
+	"time"
+)
+
+type WalletController interface {
+	ClaimCoupon(ctx context.Context, customerID string, req schema.ClaimCouponRequest) (schema.WalletCouponResponse, error)
+	GetWallet(ctx context.Context, customerID string, status string) (schema.WalletResponse, error)
+}
+
+type walletController struct {
+	l  logger.Interface
+	cs services.CouponService
+	cr repositories.CouponRepository
+	wr repositories.WalletRepository
+}
+
+func NewWalletController(l logger.Interface, cs services.CouponService, cr repositories.CouponRepository, wr repositories.WalletRepository) WalletController {
+	return &walletController{
+		l:  l,
+		cs: cs,
+		cr: cr,
+		wr: wr,
+	}
+}
+
+// ClaimCoupon adds the coupon to the customer's wallet. Coupons with a limited
+// number of claims go through the flash sale claim path instead.
+func (c *walletController) ClaimCoupon(ctx context.Context, customerID string, req schema.ClaimCouponRequest) (schema.WalletCouponResponse, error) {
+	coupon, err := c.cr.GetCouponByID(ctx, req.CouponCode)
+	if err != nil {
+		c.l.Error("Failed to get coupon by ID", "error", err, "id", req.CouponCode)
+		return schema.WalletCouponResponse{}, err
+	}
+	if coupon.ClaimLimit != nil {
+		return schema.WalletCouponResponse{}, errs.BadRequestError{
+			Message: fmt.Sprintf("coupon %s has a limited number of claims and must be claimed through /v1/coupons/%s/flash-claims", coupon.CouponCode, coupon.CouponCode),
+		}
+	}
+	now := time.Now()
+	if err := c.cs.ValidateClaim(ctx, coupon, customerID, now); err != nil {
+		return schema.WalletCouponResponse{}, errs.BadRequestError{Message: err.Error()}
+	}
+	wallet, err := c.wr.ClaimCoupon(ctx, newWalletCoupon(coupon, customerID, now))
+	if err != nil {
+		c.l.Error("Failed to claim coupon", "error", err, "coupon_code", coupon.CouponCode, "customer_id", customerID)
+		return schema.WalletCouponResponse{}, err
+	}
+	wallet.Coupon = coupon
+	used, err := c.wr.CountWalletRedemptions(ctx, customerID)
+	if err != nil {
+		c.l.Error("Failed to count wallet redemptions", "error", err, "customer_id", customerID)
+		return schema.WalletCouponResponse{}, err
+	}
+	return schema.ToWalletCouponResponse(wallet, used[wallet.CouponCode], now), nil
+}
+
+// GetWallet lists the customer's claimed coupons, optionally only those in
+// the given status.
+func (c *walletController) GetWallet(ctx context.Context, customerID string, status string) (schema.WalletResponse, error) {
+	switch model.WalletCouponStatus(status) {
+	case "", model.WalletCouponStatusAvailable, model.WalletCouponStatusUsed, model.WalletCouponStatusExpired:
+	default:
+		return schema.WalletResponse{}, errs.BadRequestError{Message: "status must be one of available, used or expired"}
+	}
+	wallet, err := c.wr.GetWalletCoupons(ctx, customerID)
+	if err != nil {
+		c.l.Error("Failed to get wallet coupons", "error", err, "customer_id", customerID)
+		return schema.WalletResponse{}, err
+	}
+	used, err := c.wr.CountWalletRedemptions(ctx, customerID)
+	if err != nil {
+		c.l.Error("Failed to count wallet redemptions", "error", err, "customer_id", customerID)
+		return schema.WalletResponse{}, err
+	}
+
+	now := time.Now()
+	response := schema.WalletResponse{
+		CustomerID: customerID,
+		Coupons:    make([]schema.WalletCouponResponse, 0, len(wallet)),
+	}
+	for _, claim := range wallet {
+		coupon := schema.ToWalletCouponResponse(claim, used[claim.CouponCode], now)
+		if coupon.Status == model.WalletCouponStatusAvailable {
+			response.Available++
+		}
+		if status == "" || coupon.Status == model.WalletCouponStatus(status) {
+			response.Coupons = append(response.Coupons, coupon)
+		}
+	}
+	return response, nil
+}
+
+// newWalletCoupon builds the customer's claim on the coupon made at the given
+// time. The claim expires with the coupon, or ClaimValidityDays after it is
+// made if that comes first.
+func newWalletCoupon(coupon model.Coupon, customerID string, at time.Time) model.WalletCoupon {
+	expiresAt := coupon.ExpiredAt
+	if coupon.ClaimValidityDays != nil {
+		if claimEnd := at.AddDate(0, 0, *coupon.ClaimValidityDays); claimEnd.Before(expiresAt) {
+			expiresAt = claimEnd
+		}
+	}
+	return model.WalletCoupon{
+		CustomerID: customerID,
+		CouponCode: coupon.CouponCode,
+		ClaimedAt:  at,
+		ExpiresAt:  expiresAt,
+	}
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"coupon-be/pkg/money"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Campaign groups coupons run as one promotion, such as a summer sale. Its
+// coupons can only be redeemed between StartsAt and EndsAt, and Budget caps
+// the total discount they give together.
+type Campaign struct {
+	ID          uint64         `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	Name        string         `json:"name" gorm:"column:name;type:varchar(255);not null"`
+	Description string         `json:"description" gorm:"column:description;type:text;not null"`
+	Owner       string         `json:"owner" gorm:"column:owner;type:varchar(255);not null"`
+	Budget      *money.Amount  `json:"budget" gorm:"column:budget;type:decimal(15,2)"`
+	Currency    money.Currency `json:"currency" gorm:"column:currency;type:char(3);not null;default:'VND'"`
+	StartsAt    time.Time      `json:"starts_at" gorm:"column:starts_at;type:datetime;not null"`
+	EndsAt      time.Time      `json:"ends_at" gorm:"column:ends_at;type:datetime;not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"column:deleted_at;index"`
+}
+
+// CampaignStats aggregates the redemptions of every coupon of a campaign.
+type CampaignStats struct {
+	Coupons        int64        `json:"coupons"`
+	Redemptions    int64        `json:"redemptions"`
+	Orders         int64        `json:"orders"`
+	Customers      int64        `json:"customers"`
+	DiscountAmount money.Amount `json:"discount_amount"`
+}
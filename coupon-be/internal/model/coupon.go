@@ -1,25 +1,101 @@
 package model
 
-import "time"
+import (
+	"coupon-be/pkg/money"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type CouponType string
 type CouponUsage string
+type CouponStatus string
+
+const (
+	CouponTypeFixed      CouponType = "fixed"
+	CouponTypePercentage CouponType = "percentage"
+	// CouponTypeFreeShipping waives the shipping fee of an order, up to
+	// MaxDiscountAmount if set. It ignores CouponValue.
+	CouponTypeFreeShipping CouponType = "free_shipping"
+	// CouponTypeTiered takes the discount of the highest tier the order
+	// reaches off the subtotal. It ignores CouponValue.
+	CouponTypeTiered CouponType = "tiered"
+	// CouponTypeBuyXGetY discounts reward items for every set of trigger
+	// items in the order, following BuyXGetY. It ignores CouponValue.
+	CouponTypeBuyXGetY CouponType = "buy_x_get_y"
+)
 
 const (
-	CouponTypeFixed      CouponType  = "fixed"
-	CouponTypePercentage CouponType  = "percentage"
-	CouponUsageManual    CouponUsage = "manual"
-	CouponUsageAuto      CouponUsage = "auto"
+	CouponUsageManual    CouponUsage  = "manual"
+	CouponUsageAuto      CouponUsage  = "auto"
+	CouponStatusDraft    CouponStatus = "draft"
+	CouponStatusActive   CouponStatus = "active"
+	CouponStatusPaused   CouponStatus = "paused"
+	CouponStatusArchived CouponStatus = "archived"
 )
 
 type Coupon struct {
-	CouponCode  string      `json:"coupon_code" gorm:"column:coupon_code;type:varchar(255);primaryKey"`
-	Title       string      `json:"title" gorm:"column:title;type:varchar(255);not null"`
-	Description string      `json:"description" gorm:"column:description;type:text;not null"`
-	CouponType  CouponType  `json:"coupon_type" gorm:"column:coupon_type;type:enum('fixed','percentage');not null"`
-	Usage       CouponUsage `json:"usage" gorm:"column:usage;type:enum('manual','auto');not null"`
-	ExpiredAt   time.Time   `json:"expired_at" gorm:"column:expired_at;type:datetime;not null"`
-	CouponValue float64     `json:"coupon_value" gorm:"column:coupon_value;type:decimal(10,2);not null"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+	CouponCode                string           `json:"coupon_code" gorm:"column:coupon_code;type:varchar(255);primaryKey"`
+	Title                     string           `json:"title" gorm:"column:title;type:varchar(255);not null"`
+	Description               string           `json:"description" gorm:"column:description;type:text;not null"`
+	CouponType                CouponType       `json:"coupon_type" gorm:"column:coupon_type;type:varchar(32);not null"`
+	Usage                     CouponUsage      `json:"usage" gorm:"column:usage;type:enum('manual','auto');not null"`
+	Status                    CouponStatus     `json:"status" gorm:"column:status;type:enum('draft','active','paused','archived');not null;default:active"`
+	StackingGroup             *string          `json:"stacking_group" gorm:"column:stacking_group;type:varchar(255)"`
+	Exclusive                 bool             `json:"exclusive" gorm:"column:exclusive;not null;default:false"`
+	Priority                  int              `json:"priority" gorm:"column:priority;not null;default:0"`
+	StartsAt                  *time.Time       `json:"starts_at" gorm:"column:starts_at;type:datetime"`
+	ExpiredAt                 time.Time        `json:"expired_at" gorm:"column:expired_at;type:datetime;not null"`
+	ValidityWindows           CouponWindows    `json:"validity_windows" gorm:"column:validity_windows;type:json"`
+	TimeZone                  *string          `json:"time_zone" gorm:"column:time_zone;type:varchar(64)"`
+	CouponValue               money.Amount     `json:"coupon_value" gorm:"column:coupon_value;type:decimal(15,2);not null"`
+	Currency                  money.Currency   `json:"currency" gorm:"column:currency;type:char(3);not null;default:'VND'"`
+	Tiers                     CouponTiers      `json:"tiers" gorm:"column:tiers;type:json"`
+	Targeting                 *CouponTargeting `json:"targeting" gorm:"column:targeting;type:json"`
+	BuyXGetY                  *CouponBuyXGetY  `json:"buy_x_get_y" gorm:"column:buy_x_get_y;type:json"`
+	AllowedCustomerIDs        StringList       `json:"allowed_customer_ids" gorm:"column:allowed_customer_ids;type:json"`
+	CustomerSegment           *string          `json:"customer_segment" gorm:"column:customer_segment;type:varchar(255)"`
+	FirstOrderOnly            bool             `json:"first_order_only" gorm:"column:first_order_only;not null;default:false"`
+	Channels                  StringList       `json:"channels" gorm:"column:channels;type:json"`
+	PaymentMethods            StringList       `json:"payment_methods" gorm:"column:payment_methods;type:json"`
+	ParentCouponCode          *string          `json:"parent_coupon_code" gorm:"column:parent_coupon_code;type:varchar(255);index"`
+	BatchID                   *uint64          `json:"batch_id" gorm:"column:batch_id;index"`
+	CampaignID                *uint64          `json:"campaign_id" gorm:"column:campaign_id;index"`
+	MaxRedemptions            *int             `json:"max_redemptions" gorm:"column:max_redemptions;type:int"`
+	MaxRedemptionsPerCustomer *int             `json:"max_redemptions_per_customer" gorm:"column:max_redemptions_per_customer;type:int"`
+	MinOrderAmount            *money.Amount    `json:"min_order_amount" gorm:"column:min_order_amount;type:decimal(15,2)"`
+	MaxDiscountAmount         *money.Amount    `json:"max_discount_amount" gorm:"column:max_discount_amount;type:decimal(15,2)"`
+	// Budget caps the total discount the coupon gives across all orders.
+	// Orders that would go over it are rejected, or get what is left of it
+	// if AllowPartialDiscount is set. Codes generated by a batch share the
+	// budget of their parent, see BudgetCode.
+	Budget               *money.Amount `json:"budget" gorm:"column:budget;type:decimal(15,2)"`
+	AllowPartialDiscount bool          `json:"allow_partial_discount" gorm:"column:allow_partial_discount;not null;default:false"`
+	// RequiresClaim coupons can only be redeemed by customers who claimed
+	// them into their wallet. Claims last ClaimValidityDays, if set.
+	// ClaimLimit caps the number of claims for flash sales.
+	RequiresClaim     bool           `json:"requires_claim" gorm:"column:requires_claim;not null;default:false"`
+	ClaimValidityDays *int           `json:"claim_validity_days" gorm:"column:claim_validity_days;type:int"`
+	ClaimLimit        *int           `json:"claim_limit" gorm:"column:claim_limit;type:int"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"column:deleted_at;index"`
+}
+
+// Location returns the time zone the coupon's validity windows are evaluated
+// in, UTC if none is set.
+func (c Coupon) Location() (*time.Location, error) {
+	if c.TimeZone == nil {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(*c.TimeZone)
+}
+
+// BudgetCode returns the code whose budget the coupon draws from: the parent
+// of a code generated by a batch, the coupon itself otherwise.
+func (c Coupon) BudgetCode() string {
+	if c.ParentCouponCode != nil {
+		return *c.ParentCouponCode
+	}
+	return c.CouponCode
 }
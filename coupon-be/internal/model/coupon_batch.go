@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type CouponBatchStatus string
+
+const (
+	CouponBatchStatusPending   CouponBatchStatus = "pending"
+	CouponBatchStatusRunning   CouponBatchStatus = "running"
+	CouponBatchStatusCompleted CouponBatchStatus = "completed"
+	CouponBatchStatusFailed    CouponBatchStatus = "failed"
+)
+
+// CouponBatch is a run generating single-use codes for a parent coupon. Each
+// generated code is stored as a coupon copying the parent's offer, with its
+// ParentCouponCode and BatchID set.
+type CouponBatch struct {
+	ID               uint64            `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ParentCouponCode string            `json:"parent_coupon_code" gorm:"column:parent_coupon_code;type:varchar(255);not null;index"`
+	Quantity         int               `json:"quantity" gorm:"column:quantity;not null"`
+	Generated        int               `json:"generated" gorm:"column:generated_count;not null;default:0"`
+	Prefix           string            `json:"prefix" gorm:"column:prefix;type:varchar(32);not null;default:''"`
+	Alphabet         string            `json:"alphabet" gorm:"column:alphabet;type:varchar(128);not null"`
+	Length           int               `json:"length" gorm:"column:length;not null"`
+	CheckDigit       bool              `json:"check_digit" gorm:"column:check_digit;not null;default:false"`
+	Status           CouponBatchStatus `json:"status" gorm:"column:status;type:enum('pending','running','completed','failed');not null;default:pending"`
+	Error            *string           `json:"error" gorm:"column:error;type:text"`
+	CompletedAt      *time.Time        `json:"completed_at" gorm:"column:completed_at;type:datetime"`
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+}
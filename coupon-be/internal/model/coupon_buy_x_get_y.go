@@ -0,0 +1,71 @@
+package model
+
+import (
+	"coupon-be/pkg/money"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// CouponItemRule picks order items by SKU or category. An item matches if
+// either list names it.
+type CouponItemRule struct {
+	SKUs       []string `json:"skus,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Quantity   int      `json:"quantity"`
+}
+
+// Matches reports whether an item with the SKU and category is picked by the
+// rule.
+func (r CouponItemRule) Matches(sku, category string) bool {
+	return contains(r.SKUs, sku) || contains(r.Categories, category)
+}
+
+// CouponBuyXGetY holds the rules of a buy-X-get-Y coupon: every Buy.Quantity
+// items matching Buy earn Get.Quantity items matching Get at Discount percent
+// off. Discount defaults to 100, making the reward items free. MaxSets limits
+// how many times one order earns the reward.
+type CouponBuyXGetY struct {
+	Buy      CouponItemRule `json:"buy"`
+	Get      CouponItemRule `json:"get"`
+	Discount *money.Amount  `json:"discount,omitempty"`
+	MaxSets  *int           `json:"max_sets,omitempty"`
+}
+
+// RewardDiscount returns the percent taken off each reward item.
+func (b CouponBuyXGetY) RewardDiscount() money.Amount {
+	if b.Discount == nil {
+		return money.NewFromInt(100)
+	}
+	return *b.Discount
+}
+
+// Value implements driver.Valuer.
+func (b CouponBuyXGetY) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+// Scan implements sql.Scanner. Coupons without rules are stored as NULL and
+// scan into the zero rules.
+func (b *CouponBuyXGetY) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*b = CouponBuyXGetY{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	default:
+		return fmt.Errorf("cannot scan %T into CouponBuyXGetY", value)
+	}
+}
+
+// MarshalBinary encodes the rules for the Redis cache.
+func (b CouponBuyXGetY) MarshalBinary() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+func (b *CouponBuyXGetY) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, b)
+}
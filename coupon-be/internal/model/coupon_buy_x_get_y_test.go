@@ -0,0 +1,60 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCouponBuyXGetYScan(t *testing.T) {
+	maxSets := 2
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    CouponBuyXGetY
+		wantErr bool
+	}{
+		{
+			name:  "NULL column",
+			value: nil,
+			want:  CouponBuyXGetY{},
+		},
+		{
+			name:  "JSON bytes",
+			value: []byte(`{"buy":{"skus":["A"],"quantity":2},"get":{"skus":["B"],"quantity":1},"max_sets":2}`),
+			want: CouponBuyXGetY{
+				Buy:     CouponItemRule{SKUs: []string{"A"}, Quantity: 2},
+				Get:     CouponItemRule{SKUs: []string{"B"}, Quantity: 1},
+				MaxSets: &maxSets,
+			},
+		},
+		{
+			name:  "JSON string",
+			value: `{"buy":{"categories":["shoes"],"quantity":1},"get":{"categories":["socks"],"quantity":1}}`,
+			want: CouponBuyXGetY{
+				Buy: CouponItemRule{Categories: []string{"shoes"}, Quantity: 1},
+				Get: CouponItemRule{Categories: []string{"socks"}, Quantity: 1},
+			},
+		},
+		{
+			name:    "unsupported type",
+			value:   42,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got CouponBuyXGetY
+			if tt.value == nil {
+				// NULL must reset a previously scanned value.
+				got.Buy = CouponItemRule{SKUs: []string{"stale"}, Quantity: 1}
+			}
+			err := got.Scan(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// CouponTargeting limits a coupon to some of the items of an order. An item
+// is eligible if it matches an include list, or if both include lists are
+// empty, and matches neither exclude list.
+type CouponTargeting struct {
+	IncludeSKUs       []string `json:"include_skus,omitempty"`
+	ExcludeSKUs       []string `json:"exclude_skus,omitempty"`
+	IncludeCategories []string `json:"include_categories,omitempty"`
+	ExcludeCategories []string `json:"exclude_categories,omitempty"`
+}
+
+// IsEmpty reports whether the targeting lets every item through.
+func (t CouponTargeting) IsEmpty() bool {
+	return len(t.IncludeSKUs) == 0 && len(t.ExcludeSKUs) == 0 &&
+		len(t.IncludeCategories) == 0 && len(t.ExcludeCategories) == 0
+}
+
+// Matches reports whether an item with the SKU and category is eligible.
+func (t CouponTargeting) Matches(sku, category string) bool {
+	if contains(t.ExcludeSKUs, sku) || contains(t.ExcludeCategories, category) {
+		return false
+	}
+	if len(t.IncludeSKUs) == 0 && len(t.IncludeCategories) == 0 {
+		return true
+	}
+	return contains(t.IncludeSKUs, sku) || contains(t.IncludeCategories, category)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements driver.Valuer.
+func (t CouponTargeting) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+// Scan implements sql.Scanner. Coupons without targeting are stored as NULL
+// and scan into the empty targeting.
+func (t *CouponTargeting) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = CouponTargeting{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	default:
+		return fmt.Errorf("cannot scan %T into CouponTargeting", value)
+	}
+}
+
+// MarshalBinary encodes the targeting for the Redis cache.
+func (t CouponTargeting) MarshalBinary() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+func (t *CouponTargeting) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, t)
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCouponTargetingScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    CouponTargeting
+		wantErr bool
+	}{
+		{
+			name:  "NULL column",
+			value: nil,
+			want:  CouponTargeting{},
+		},
+		{
+			name:  "JSON bytes",
+			value: []byte(`{"include_skus":["A"],"exclude_categories":["gift"]}`),
+			want:  CouponTargeting{IncludeSKUs: []string{"A"}, ExcludeCategories: []string{"gift"}},
+		},
+		{
+			name:  "JSON string",
+			value: `{"include_categories":["shoes"]}`,
+			want:  CouponTargeting{IncludeCategories: []string{"shoes"}},
+		},
+		{
+			name:    "unsupported type",
+			value:   42,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got CouponTargeting
+			if tt.value == nil {
+				// NULL must reset a previously scanned value.
+				got.IncludeSKUs = []string{"stale"}
+			}
+			err := got.Scan(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
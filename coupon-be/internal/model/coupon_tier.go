@@ -0,0 +1,70 @@
+package model
+
+import (
+	"coupon-be/pkg/money"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// CouponTier is one step of a tiered coupon: orders of at least Threshold
+// get Discount percent off.
+type CouponTier struct {
+	Threshold money.Amount `json:"threshold"`
+	Discount  money.Amount `json:"discount"`
+}
+
+// CouponTiers are stored as a JSON array, lowest threshold first.
+type CouponTiers []CouponTier
+
+// Select returns the index of the highest tier amount reaches, or -1 if it
+// reaches none.
+func (t CouponTiers) Select(amount money.Amount) int {
+	selected := -1
+	for i, tier := range t {
+		if !amount.LessThan(tier.Threshold) {
+			selected = i
+		}
+	}
+	return selected
+}
+
+// Value implements driver.Valuer.
+func (t CouponTiers) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
+
+// Scan implements sql.Scanner.
+func (t *CouponTiers) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, t)
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	default:
+		return fmt.Errorf("cannot scan %T into CouponTiers", value)
+	}
+}
+
+// MarshalBinary encodes the tiers for the Redis cache; coupons without tiers
+// are cached as an empty string like other nullable fields.
+func (t CouponTiers) MarshalBinary() ([]byte, error) {
+	if t == nil {
+		return []byte{}, nil
+	}
+	return json.Marshal(t)
+}
+
+func (t *CouponTiers) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*t = nil
+		return nil
+	}
+	return json.Unmarshal(data, t)
+}
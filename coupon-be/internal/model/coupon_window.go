@@ -0,0 +1,147 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// CouponWindow is a recurring period a coupon is valid in: on Weekdays
+// ("mon" to "sun", every day if empty) from Start up to End, both "HH:MM"
+// wall clock times in the coupon's time zone. A window ending at or before
+// its start runs past midnight into the next day.
+type CouponWindow struct {
+	Weekdays []string `json:"weekdays,omitempty"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+}
+
+// Validate rejects unknown weekdays and malformed times.
+func (w CouponWindow) Validate() error {
+	for _, day := range w.Weekdays {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid weekday %q", day)
+		}
+	}
+	if _, err := parseClock(w.Start); err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	if _, err := parseClock(w.End); err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	return nil
+}
+
+// Contains reports whether the wall clock time t falls in the window. t must
+// already be in the coupon's time zone.
+func (w CouponWindow) Contains(t time.Time) bool {
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return w.onDay(t.Weekday()) && minute >= start && minute < end
+	}
+	// The window runs past midnight: the early hours belong to the window
+	// that started the day before.
+	if minute >= start {
+		return w.onDay(t.Weekday())
+	}
+	return minute < end && w.onDay((t.Weekday()+6)%7)
+}
+
+func (w CouponWindow) onDay(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+	for _, name := range w.Weekdays {
+		if weekdays[strings.ToLower(name)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock returns the minutes since midnight of an "HH:MM" time. "24:00"
+// is accepted as the end of the day.
+func parseClock(value string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(value, "%d:%d", &hour, &minute); err != nil || len(value) != 5 {
+		return 0, fmt.Errorf("%q is not an HH:MM time", value)
+	}
+	if hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("%q is not an HH:MM time", value)
+	}
+	return hour*60 + minute, nil
+}
+
+// CouponWindows are stored as a JSON array. A coupon without windows is valid
+// at any time between StartsAt and ExpiredAt.
+type CouponWindows []CouponWindow
+
+// Contains reports whether t falls in any of the windows.
+func (w CouponWindows) Contains(t time.Time) bool {
+	for _, window := range w {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements driver.Valuer.
+func (w CouponWindows) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return json.Marshal(w)
+}
+
+// Scan implements sql.Scanner.
+func (w *CouponWindows) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, w)
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	default:
+		return fmt.Errorf("cannot scan %T into CouponWindows", value)
+	}
+}
+
+// MarshalBinary encodes the windows for the Redis cache; coupons without
+// windows are cached as an empty string like other nullable fields.
+func (w CouponWindows) MarshalBinary() ([]byte, error) {
+	if w == nil {
+		return []byte{}, nil
+	}
+	return json.Marshal(w)
+}
+
+func (w *CouponWindows) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*w = nil
+		return nil
+	}
+	return json.Unmarshal(data, w)
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// CustomerSegment records that a customer belongs to a named segment, such
+// as "vip" or "service-recovery".
+type CustomerSegment struct {
+	CustomerID string    `json:"customer_id" gorm:"column:customer_id;type:varchar(255);primaryKey"`
+	Segment    string    `json:"segment" gorm:"column:segment;type:varchar(255);primaryKey;index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CustomerOrderLock is locked by orders that depend on the customer's order
+// history, such as orders redeeming first-order-only coupons, so concurrent
+// orders of the same customer are checked one after the other.
+type CustomerOrderLock struct {
+	CustomerID string `gorm:"column:customer_id;type:varchar(255);primaryKey"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"coupon-be/pkg/money"
+	"time"
+)
+
+type Order struct {
+	ID             uint64       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	CustomerID     *string      `json:"customer_id" gorm:"column:customer_id;type:varchar(255);index"`
+	Channel        *string      `json:"channel" gorm:"column:channel;type:varchar(64)"`
+	PaymentMethod  *string      `json:"payment_method" gorm:"column:payment_method;type:varchar(64)"`
+	Cost           money.Amount `json:"cost" gorm:"column:cost;type:decimal(15,2);not null"`
+	DiscountAmount money.Amount `json:"discount_amount" gorm:"column:discount_amount;type:decimal(15,2);not null;default:0"`
+	ShippingFee    money.Amount `json:"shipping_fee" gorm:"column:shipping_fee;type:decimal(15,2);not null;default:0"`
+	// ShippingDiscountAmount is the part of the shipping fee waived by
+	// coupons; DiscountAmount only covers the goods.
+	ShippingDiscountAmount money.Amount       `json:"shipping_discount_amount" gorm:"column:shipping_discount_amount;type:decimal(15,2);not null;default:0"`
+	TotalAmount            money.Amount       `json:"total_amount" gorm:"column:total_amount;type:decimal(15,2);not null"`
+	Currency               money.Currency     `json:"currency" gorm:"column:currency;type:char(3);not null;default:'VND'"`
+	Items                  []OrderItem        `json:"items" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	Redemptions            []CouponRedemption `json:"redemptions" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	CreatedAt              time.Time          `json:"created_at"`
+	UpdatedAt              time.Time          `json:"updated_at"`
+}
+
+// OrderItem is one line of an order with the discount coupons took off it.
+type OrderItem struct {
+	ID             uint64       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	OrderID        uint64       `json:"order_id" gorm:"column:order_id;not null;index"`
+	SKU            string       `json:"sku" gorm:"column:sku;type:varchar(255);not null"`
+	Category       string       `json:"category" gorm:"column:category;type:varchar(255);not null;default:''"`
+	Quantity       int          `json:"quantity" gorm:"column:quantity;not null"`
+	UnitPrice      money.Amount `json:"unit_price" gorm:"column:unit_price;type:decimal(15,2);not null"`
+	DiscountAmount money.Amount `json:"discount_amount" gorm:"column:discount_amount;type:decimal(15,2);not null;default:0"`
+	TotalAmount    money.Amount `json:"total_amount" gorm:"column:total_amount;type:decimal(15,2);not null"`
+}
+
+type CouponRedemption struct {
+	ID             uint64       `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	OrderID        uint64       `json:"order_id" gorm:"column:order_id;not null;index"`
+	CouponCode     string       `json:"coupon_code" gorm:"column:coupon_code;type:varchar(255);not null;index"`
+	DiscountAmount money.Amount `json:"discount_amount" gorm:"column:discount_amount;type:decimal(15,2);not null"`
+	AutoApplied    bool         `json:"auto_applied" gorm:"column:auto_applied;not null;default:false"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringList is a list of strings stored as a JSON array. A nil list is
+// stored as NULL.
+type StringList []string
+
+// Contains reports whether the list holds value.
+func (l StringList) Contains(value string) bool {
+	return contains(l, value)
+}
+
+// Value implements driver.Valuer.
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan implements sql.Scanner.
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+}
+
+// MarshalBinary encodes the list for the Redis cache; nil lists are cached as
+// an empty string like other nullable fields.
+func (l StringList) MarshalBinary() ([]byte, error) {
+	if l == nil {
+		return []byte{}, nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *StringList) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		*l = nil
+		return nil
+	}
+	return json.Unmarshal(data, l)
+}
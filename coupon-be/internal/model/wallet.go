@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type WalletCouponStatus string
+
+const (
+	WalletCouponStatusAvailable WalletCouponStatus = "available"
+	WalletCouponStatusUsed      WalletCouponStatus = "used"
+	WalletCouponStatusExpired   WalletCouponStatus = "expired"
+)
+
+// WalletCoupon is a coupon a customer claimed into their wallet. The claim
+// expires at ExpiresAt, which is never later than the coupon's own expiry.
+type WalletCoupon struct {
+	ID         uint64    `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	CustomerID string    `json:"customer_id" gorm:"column:customer_id;type:varchar(255);not null;uniqueIndex:idx_wallet_coupons_customer_coupon"`
+	CouponCode string    `json:"coupon_code" gorm:"column:coupon_code;type:varchar(255);not null;uniqueIndex:idx_wallet_coupons_customer_coupon;index"`
+	ClaimedAt  time.Time `json:"claimed_at" gorm:"column:claimed_at;type:datetime;not null"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"column:expires_at;type:datetime;not null"`
+	Coupon     Coupon    `json:"-" gorm:"foreignKey:CouponCode;references:CouponCode"`
+}
+
+// Expired reports whether the claim has expired at the given time.
+func (w WalletCoupon) Expired(at time.Time) bool {
+	return !at.Before(w.ExpiresAt)
+}
+
+// RemainingUses returns how many more times the customer may redeem the
+// coupon after used redemptions, or nil if the coupon sets no per-customer
+// limit.
+func (w WalletCoupon) RemainingUses(used int64) *int {
+	if w.Coupon.MaxRedemptionsPerCustomer == nil {
+		return nil
+	}
+	remaining := max(*w.Coupon.MaxRedemptionsPerCustomer-int(used), 0)
+	return &remaining
+}
+
+// Status returns the state of the claim at the given time after used
+// redemptions.
+func (w WalletCoupon) Status(at time.Time, used int64) WalletCouponStatus {
+	if w.Expired(at) {
+		return WalletCouponStatusExpired
+	}
+	if remaining := w.RemainingUses(used); remaining != nil && *remaining == 0 {
+		return WalletCouponStatusUsed
+	}
+	return WalletCouponStatusAvailable
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type CampaignRepository interface {
+	CreateCampaign(ctx context.Context, campaign model.Campaign) (model.Campaign, error)
+	GetCampaignsWithTotal(ctx context.Context, offset, limit int) ([]model.Campaign, int64, error)
+	GetCampaignByID(ctx context.Context, id uint64) (model.Campaign, error)
+	UpdateCampaign(ctx context.Context, id uint64, data map[string]any) (model.Campaign, error)
+	DeleteCampaign(ctx context.Context, id uint64) error
+	GetCampaignStats(ctx context.Context, id uint64) (model.CampaignStats, error)
+}
+
+type campaignRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCampaignRepository(db *gorm.DB) CampaignRepository {
+	return &campaignRepositoryImpl{db: db}
+}
+
+func (r *campaignRepositoryImpl) CreateCampaign(ctx context.Context, campaign model.Campaign) (model.Campaign, error) {
+	if err := r.db.WithContext(ctx).Create(&campaign).Error; err != nil {
+		return model.Campaign{}, err
+	}
+	return campaign, nil
+}
+
+func (r *campaignRepositoryImpl) GetCampaignsWithTotal(ctx context.Context, offset, limit int) ([]model.Campaign, int64, error) {
+	var campaigns []model.Campaign
+	var total int64
+	tx := r.db.WithContext(ctx).Model(&model.Campaign{}).Count(&total)
+	if offset != 0 || limit != 0 {
+		tx = tx.Offset(offset).Limit(limit)
+	}
+	if err := tx.Order("id DESC").Find(&campaigns).Error; err != nil {
+		return nil, 0, err
+	}
+	return campaigns, total, nil
+}
+
+func (r *campaignRepositoryImpl) GetCampaignByID(ctx context.Context, id uint64) (model.Campaign, error) {
+	var campaign model.Campaign
+	if err := r.db.WithContext(ctx).First(&campaign, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.Campaign{}, errs.NotFoundError{Message: fmt.Sprintf("Campaign with ID %d not found", id)}
+		}
+		return model.Campaign{}, err
+	}
+	return campaign, nil
+}
+
+func (r *campaignRepositoryImpl) UpdateCampaign(ctx context.Context, id uint64, data map[string]any) (model.Campaign, error) {
+	tx := r.db.WithContext(ctx).Model(&model.Campaign{}).Where("id = ?", id).Updates(data)
+	if tx.Error != nil {
+		return model.Campaign{}, tx.Error
+	}
+	return r.GetCampaignByID(ctx, id)
+}
+
+// DeleteCampaign soft-deletes a campaign that no longer has coupons. Deleted
+// coupons count too, since restoring one would leave it in a missing
+// campaign.
+func (r *campaignRepositoryImpl) DeleteCampaign(ctx context.Context, id uint64) error {
+	if _, err := r.GetCampaignByID(ctx, id); err != nil {
+		return err
+	}
+	var coupons int64
+	if err := r.db.WithContext(ctx).Unscoped().Model(&model.Coupon{}).Where("campaign_id = ?", id).Count(&coupons).Error; err != nil {
+		return err
+	}
+	if coupons > 0 {
+		return errs.BadRequestError{Message: fmt.Sprintf("Campaign with ID %d still has %d coupons, including deleted ones", id, coupons)}
+	}
+	return r.db.WithContext(ctx).Delete(&model.Campaign{}, "id = ?", id).Error
+}
+
+// GetCampaignStats counts the campaign's coupons and aggregates the
+// redemptions of all of them, including coupons deleted since.
+func (r *campaignRepositoryImpl) GetCampaignStats(ctx context.Context, id uint64) (model.CampaignStats, error) {
+	stats := model.CampaignStats{DiscountAmount: money.Zero}
+	if err := r.db.WithContext(ctx).Model(&model.Coupon{}).Where("campaign_id = ?", id).Count(&stats.Coupons).Error; err != nil {
+		return model.CampaignStats{}, err
+	}
+	err := r.db.WithContext(ctx).Table("coupon_redemptions").
+		Select("COUNT(*) AS redemptions, COUNT(DISTINCT coupon_redemptions.order_id) AS orders, "+
+			"COUNT(DISTINCT orders.customer_id) AS customers, COALESCE(SUM(coupon_redemptions.discount_amount), 0) AS discount_amount").
+		Joins("JOIN coupons ON coupons.coupon_code = coupon_redemptions.coupon_code").
+		Joins("JOIN orders ON orders.id = coupon_redemptions.order_id").
+		Where("coupons.campaign_id = ?", id).
+		Scan(&stats).Error
+	if err != nil {
+		return model.CampaignStats{}, err
+	}
+	return stats, nil
+}
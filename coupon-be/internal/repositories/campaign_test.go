@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestDeleteCampaignWithDeletedCoupons(t *testing.T) {
+	db, err := gorm.Open(mysql.Open("root:123123@tcp(localhost:3306)/zalopay?charset=utf8mb4&parseTime=True&loc=Local"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	campaign := model.Campaign{
+		Name:     "Deleted Coupons Campaign",
+		Owner:    "marketing",
+		Currency: "VND",
+		StartsAt: time.Now(),
+		EndsAt:   time.Now().AddDate(0, 1, 0),
+	}
+	if err := db.Create(&campaign).Error; err != nil {
+		t.Fatalf("Failed to seed database: %v", err)
+	}
+	coupon := model.Coupon{
+		CouponCode:  "CAMPAIGNDELETED",
+		Title:       "Campaign Coupon",
+		Description: "Description for Campaign Coupon",
+		CouponType:  model.CouponTypeFixed,
+		Usage:       model.CouponUsageManual,
+		ExpiredAt:   time.Now().AddDate(0, 0, 10),
+		CouponValue: money.NewFromInt(10000),
+		CampaignID:  &campaign.ID,
+	}
+	if err := db.Create(&coupon).Error; err != nil {
+		t.Fatalf("Failed to seed database: %v", err)
+	}
+	if err := db.Delete(&coupon).Error; err != nil {
+		t.Fatalf("Failed to delete coupon: %v", err)
+	}
+
+	repo := NewCampaignRepository(db)
+	err = repo.DeleteCampaign(context.Background(), campaign.ID)
+	want := errs.BadRequestError{Message: fmt.Sprintf("Campaign with ID %d still has 1 coupons, including deleted ones", campaign.ID)}
+	if err == nil || err.Error() != want.Error() {
+		t.Errorf("DeleteCampaign(), error = %v, wantErr %v", err, want)
+	}
+
+	if err := db.Exec("DELETE FROM coupons").Error; err != nil {
+		t.Fatalf("Failed to remove seed data: %v", err)
+	}
+	if err := db.Exec("DELETE FROM campaigns").Error; err != nil {
+		t.Fatalf("Failed to remove seed data: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"coupon-be/internal/model"
 	"coupon-be/pkg/utils/errs"
 	"fmt"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
@@ -12,11 +13,15 @@ import (
 
 type CouponRepository interface {
 	GetCouponsWithTotal(ctx context.Context, offset, limit int) ([]model.Coupon, int64, error)
-	SearchCouponsWithTotal(ctx context.Context, offset, limit int, where string) ([]model.Coupon, int64, error)
+	SearchCouponsWithTotal(ctx context.Context, offset, limit int, couponCode, channel, paymentMethod string) ([]model.Coupon, int64, error)
 	GetCouponByID(ctx context.Context, id string) (model.Coupon, error)
 	CreateCoupon(ctx context.Context, coupon model.Coupon) (model.Coupon, error)
 	UpdateCoupon(ctx context.Context, id string, data map[string]any) (model.Coupon, error)
 	DeleteCoupon(ctx context.Context, id string) error
+	RestoreCoupon(ctx context.Context, id string) (model.Coupon, error)
+	GetDeletedCouponsWithTotal(ctx context.Context, offset, limit int) ([]model.Coupon, int64, error)
+	GetActiveCoupons(ctx context.Context, at time.Time) ([]model.Coupon, error)
+	GetActiveAutoCoupons(ctx context.Context, at time.Time) ([]model.Coupon, error)
 }
 
 type couponRepositoryImpl struct {
@@ -85,10 +90,23 @@ func (r *couponRepositoryImpl) DeleteCoupon(ctx context.Context, id string) erro
 	return nil
 }
 
-func (r *couponRepositoryImpl) SearchCouponsWithTotal(ctx context.Context, offset, limit int, couponCode string) ([]model.Coupon, int64, error) {
+// SearchCouponsWithTotal filters coupons by code and by the channel and
+// payment method they can be used with. Coupons without a channel or payment
+// method restriction match any channel or payment method.
+func (r *couponRepositoryImpl) SearchCouponsWithTotal(ctx context.Context, offset, limit int, couponCode, channel, paymentMethod string) ([]model.Coupon, int64, error) {
 	var coupons []model.Coupon
 	var total int64
-	tx := r.db.WithContext(ctx).Where("coupon_code LIKE ?", fmt.Sprintf("%%%s%%", couponCode)).Model(&model.Coupon{}).Count(&total)
+	tx := r.db.WithContext(ctx).Model(&model.Coupon{})
+	if couponCode != "" {
+		tx = tx.Where("coupon_code LIKE ?", fmt.Sprintf("%%%s%%", couponCode))
+	}
+	if channel != "" {
+		tx = tx.Where("(channels IS NULL OR JSON_LENGTH(channels) = 0 OR JSON_CONTAINS(channels, JSON_QUOTE(?)))", channel)
+	}
+	if paymentMethod != "" {
+		tx = tx.Where("(payment_methods IS NULL OR JSON_LENGTH(payment_methods) = 0 OR JSON_CONTAINS(payment_methods, JSON_QUOTE(?)))", paymentMethod)
+	}
+	tx = tx.Count(&total)
 	if offset != 0 || limit != 0 {
 		tx = tx.Offset(offset).Limit(limit)
 	}
@@ -98,3 +116,56 @@ func (r *couponRepositoryImpl) SearchCouponsWithTotal(ctx context.Context, offse
 	}
 	return coupons, total, nil
 }
+
+func (r *couponRepositoryImpl) RestoreCoupon(ctx context.Context, id string) (model.Coupon, error) {
+	tx := r.db.WithContext(ctx).Unscoped().Model(&model.Coupon{}).
+		Where("coupon_code = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if tx.Error != nil {
+		return model.Coupon{}, tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return model.Coupon{}, errs.NotFoundError{Message: "Deleted coupon with ID " + id + " not found"}
+	}
+	return r.GetCouponByID(ctx, id)
+}
+
+func (r *couponRepositoryImpl) GetDeletedCouponsWithTotal(ctx context.Context, offset, limit int) ([]model.Coupon, int64, error) {
+	var coupons []model.Coupon
+	var total int64
+	tx := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Model(&model.Coupon{}).Count(&total)
+	if offset != 0 || limit != 0 {
+		tx = tx.Offset(offset).Limit(limit)
+	}
+	err := tx.Find(&coupons).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return coupons, total, nil
+}
+
+func (r *couponRepositoryImpl) GetActiveCoupons(ctx context.Context, at time.Time) ([]model.Coupon, error) {
+	var coupons []model.Coupon
+	if err := r.activeCoupons(ctx, at).Find(&coupons).Error; err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+func (r *couponRepositoryImpl) GetActiveAutoCoupons(ctx context.Context, at time.Time) ([]model.Coupon, error) {
+	var coupons []model.Coupon
+	if err := r.activeCoupons(ctx, at).Where("`usage` = ?", model.CouponUsageAuto).Find(&coupons).Error; err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+// activeCoupons selects the coupons that are live at the given time. Codes
+// generated by a batch are single-use codes handed out to one customer each,
+// so they are never offered on their own.
+func (r *couponRepositoryImpl) activeCoupons(ctx context.Context, at time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Where("status = ?", model.CouponStatusActive).
+		Where("expired_at >= ? AND (starts_at IS NULL OR starts_at <= ?)", at, at).
+		Where("parent_coupon_code IS NULL")
+}
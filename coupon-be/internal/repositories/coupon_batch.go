@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CouponBatchRepository interface {
+	CreateBatch(ctx context.Context, batch model.CouponBatch) (model.CouponBatch, error)
+	GetBatchByID(ctx context.Context, id uint64) (model.CouponBatch, error)
+	UpdateBatch(ctx context.Context, id uint64, data map[string]any) error
+	InsertBatchCoupons(ctx context.Context, batchID uint64, coupons []model.Coupon) (int, error)
+	GetBatchCouponCodes(ctx context.Context, batchID uint64) ([]string, error)
+	GetUnfinishedBatches(ctx context.Context) ([]model.CouponBatch, error)
+}
+
+type couponBatchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCouponBatchRepository(db *gorm.DB) CouponBatchRepository {
+	return &couponBatchRepositoryImpl{db: db}
+}
+
+func (r *couponBatchRepositoryImpl) CreateBatch(ctx context.Context, batch model.CouponBatch) (model.CouponBatch, error) {
+	if err := r.db.WithContext(ctx).Create(&batch).Error; err != nil {
+		return model.CouponBatch{}, err
+	}
+	return batch, nil
+}
+
+func (r *couponBatchRepositoryImpl) GetBatchByID(ctx context.Context, id uint64) (model.CouponBatch, error) {
+	var batch model.CouponBatch
+	if err := r.db.WithContext(ctx).First(&batch, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.CouponBatch{}, errs.NotFoundError{Message: fmt.Sprintf("Coupon batch with ID %d not found", id)}
+		}
+		return model.CouponBatch{}, err
+	}
+	return batch, nil
+}
+
+func (r *couponBatchRepositoryImpl) UpdateBatch(ctx context.Context, id uint64, data map[string]any) error {
+	return r.db.WithContext(ctx).Model(&model.CouponBatch{}).Where("id = ?", id).Updates(data).Error
+}
+
+// InsertBatchCoupons inserts one chunk of generated coupons in a single
+// transaction and adds them to the batch's generated count. Codes that are
+// already taken are skipped; the number of coupons actually inserted is
+// returned so the caller can generate replacements.
+func (r *couponBatchRepositoryImpl) InsertBatchCoupons(ctx context.Context, batchID uint64, coupons []model.Coupon) (int, error) {
+	var inserted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// On MySQL this becomes ON DUPLICATE KEY UPDATE coupon_code = coupon_code,
+		// which skips taken codes without affecting any row but, unlike INSERT
+		// IGNORE, still fails on any other error.
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&coupons)
+		if result.Error != nil {
+			return result.Error
+		}
+		inserted = result.RowsAffected
+		return tx.Model(&model.CouponBatch{}).Where("id = ?", batchID).
+			Update("generated_count", gorm.Expr("generated_count + ?", inserted)).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(inserted), nil
+}
+
+func (r *couponBatchRepositoryImpl) GetBatchCouponCodes(ctx context.Context, batchID uint64) ([]string, error) {
+	var codes []string
+	err := r.db.WithContext(ctx).Model(&model.Coupon{}).
+		Where("batch_id = ?", batchID).
+		Order("coupon_code").
+		Pluck("coupon_code", &codes).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// GetUnfinishedBatches returns the batches that are still pending or running,
+// oldest first.
+func (r *couponBatchRepositoryImpl) GetUnfinishedBatches(ctx context.Context) ([]model.CouponBatch, error) {
+	var batches []model.CouponBatch
+	err := r.db.WithContext(ctx).
+		Where("status IN ?", []model.CouponBatchStatus{model.CouponBatchStatusPending, model.CouponBatchStatusRunning}).
+		Order("id").
+		Find(&batches).Error
+	if err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
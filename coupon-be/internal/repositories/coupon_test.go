@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
 	"coupon-be/pkg/utils/errs"
 	"testing"
 	"time"
@@ -37,7 +38,7 @@ func InitializeCouponRepository(t *testing.T) CouponRepository {
 			CouponType:  couponType,
 			Usage:       couponUsage,
 			ExpiredAt:   time.Now().AddDate(0, 0, 10),
-			CouponValue: float64(i * 10),
+			CouponValue: money.NewFromInt(int64(i * 10)),
 		})
 		err = db.Create(&SEED_DATA[i]).Error
 		if err != nil {
@@ -236,7 +237,7 @@ func TestCreateCoupon(t *testing.T) {
 					CouponType:  "fixed",
 					Usage:       "single",
 					ExpiredAt:   time.Now().AddDate(0, 0, 10),
-					CouponValue: 100.0,
+					CouponValue: money.New(100),
 				},
 			},
 			want: want{
@@ -247,7 +248,7 @@ func TestCreateCoupon(t *testing.T) {
 					CouponType:  "fixed",
 					Usage:       "single",
 					ExpiredAt:   time.Now().AddDate(0, 0, 10),
-					CouponValue: 100.0,
+					CouponValue: money.New(100),
 				},
 				err: nil,
 			},
@@ -263,7 +264,7 @@ func TestCreateCoupon(t *testing.T) {
 					CouponType:  "fixed",
 					Usage:       "single",
 					ExpiredAt:   time.Now().AddDate(0, 0, 10),
-					CouponValue: 50.0,
+					CouponValue: money.New(50),
 				},
 			},
 			want: want{
@@ -409,3 +410,129 @@ func TestDeleteCoupon(t *testing.T) {
 	}
 	RemoveDatabaseSeed(t)
 }
+
+func TestRestoreCoupon(t *testing.T) {
+	repo := InitializeCouponRepository(t)
+	if err := repo.DeleteCoupon(context.Background(), SEED_DATA[0].CouponCode); err != nil {
+		t.Fatalf("Failed to delete coupon: %v", err)
+	}
+	type args struct {
+		ctx context.Context
+		id  string
+	}
+	type want struct {
+		coupon model.Coupon
+		err    error
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "Restore deleted coupon",
+			args: args{
+				ctx: context.Background(),
+				id:  SEED_DATA[0].CouponCode,
+			},
+			want: want{
+				coupon: SEED_DATA[0],
+				err:    nil,
+			},
+		},
+		{
+			name: "Restore coupon that is not deleted",
+			args: args{
+				ctx: context.Background(),
+				id:  SEED_DATA[1].CouponCode,
+			},
+			want: want{
+				coupon: model.Coupon{},
+				err:    errs.NotFoundError{Message: "Deleted coupon with ID " + SEED_DATA[1].CouponCode + " not found"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon, err := repo.RestoreCoupon(tt.args.ctx, tt.args.id)
+			if err != nil && (tt.want.err == nil || err.Error() != tt.want.err.Error()) {
+				t.Errorf("RestoreCoupon(), test name: %s, error = %v, wantErr %v", tt.name, err, tt.want.err)
+			}
+			if coupon.CouponCode != tt.want.coupon.CouponCode {
+				t.Errorf("RestoreCoupon(), test name: %s, coupon = %v, want %v", tt.name, coupon.CouponCode, tt.want.coupon.CouponCode)
+			}
+		})
+	}
+	RemoveDatabaseSeed(t)
+}
+
+func TestGetCouponWithNullJSONColumns(t *testing.T) {
+	repo := InitializeCouponRepository(t)
+	// Seeded coupons leave every JSON column NULL.
+	coupon, err := repo.GetCouponByID(context.Background(), SEED_DATA[0].CouponCode)
+	if err != nil {
+		t.Fatalf("GetCouponByID() error = %v", err)
+	}
+	if coupon.Targeting != nil && !coupon.Targeting.IsEmpty() {
+		t.Errorf("GetCouponByID() targeting = %+v, want none", coupon.Targeting)
+	}
+	if coupon.BuyXGetY != nil && coupon.BuyXGetY.Buy.Quantity != 0 {
+		t.Errorf("GetCouponByID() buy_x_get_y = %+v, want none", coupon.BuyXGetY)
+	}
+	if coupon.Tiers != nil || coupon.ValidityWindows != nil || coupon.AllowedCustomerIDs != nil || coupon.Channels != nil || coupon.PaymentMethods != nil {
+		t.Errorf("GetCouponByID() got JSON columns %+v, want none", coupon)
+	}
+
+	targeted := model.Coupon{
+		CouponCode:  "TESTTARGETED",
+		Title:       "Targeted Test Coupon",
+		Description: "Description for Targeted Test Coupon",
+		CouponType:  model.CouponTypeFixed,
+		Usage:       model.CouponUsageManual,
+		Status:      model.CouponStatusActive,
+		ExpiredAt:   time.Now().AddDate(0, 0, 10),
+		CouponValue: money.NewFromInt(10000),
+		Targeting:   &model.CouponTargeting{IncludeSKUs: []string{"SKU-1"}},
+	}
+	if _, err := repo.CreateCoupon(context.Background(), targeted); err != nil {
+		t.Fatalf("CreateCoupon() error = %v", err)
+	}
+	coupons, err := repo.GetActiveCoupons(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("GetActiveCoupons() error = %v", err)
+	}
+	if len(coupons) != len(SEED_DATA)+1 {
+		t.Errorf("GetActiveCoupons() got %d coupons, want %d", len(coupons), len(SEED_DATA)+1)
+	}
+	for _, c := range coupons {
+		if c.CouponCode == targeted.CouponCode && (c.Targeting == nil || !c.Targeting.Matches("SKU-1", "")) {
+			t.Errorf("GetActiveCoupons() targeting = %+v, want %+v", c.Targeting, targeted.Targeting)
+		}
+	}
+	RemoveDatabaseSeed(t)
+}
+
+func TestGetActiveCouponsSkipsBatchCodes(t *testing.T) {
+	repo := InitializeCouponRepository(t)
+	child := SEED_DATA[0]
+	child.CouponCode = "TESTCHILD"
+	child.ParentCouponCode = &SEED_DATA[0].CouponCode
+	child.Status = model.CouponStatusActive
+	if _, err := repo.CreateCoupon(context.Background(), child); err != nil {
+		t.Fatalf("CreateCoupon() error = %v", err)
+	}
+	active, err := repo.GetActiveCoupons(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("GetActiveCoupons() error = %v", err)
+	}
+	auto, err := repo.GetActiveAutoCoupons(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("GetActiveAutoCoupons() error = %v", err)
+	}
+	for _, coupon := range append(active, auto...) {
+		if coupon.CouponCode == child.CouponCode {
+			t.Errorf("got batch code %s, want only coupons that were not generated", child.CouponCode)
+		}
+	}
+	RemoveDatabaseSeed(t)
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"coupon-be/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type CustomerRepository interface {
+	GetCustomerSegments(ctx context.Context, customerID string) ([]string, error)
+	SetCustomerSegments(ctx context.Context, customerID string, segments []string) ([]string, error)
+	IsCustomerInSegment(ctx context.Context, customerID, segment string) (bool, error)
+}
+
+type customerRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCustomerRepository(db *gorm.DB) CustomerRepository {
+	return &customerRepositoryImpl{db: db}
+}
+
+func (r *customerRepositoryImpl) GetCustomerSegments(ctx context.Context, customerID string) ([]string, error) {
+	segments := []string{}
+	err := r.db.WithContext(ctx).Model(&model.CustomerSegment{}).
+		Where("customer_id = ?", customerID).
+		Order("segment").
+		Pluck("segment", &segments).Error
+	if err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// SetCustomerSegments replaces every segment of the customer.
+func (r *customerRepositoryImpl) SetCustomerSegments(ctx context.Context, customerID string, segments []string) ([]string, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("customer_id = ?", customerID).Delete(&model.CustomerSegment{}).Error; err != nil {
+			return err
+		}
+		if len(segments) == 0 {
+			return nil
+		}
+		rows := make([]model.CustomerSegment, 0, len(segments))
+		seen := make(map[string]bool, len(segments))
+		for _, segment := range segments {
+			if seen[segment] {
+				continue
+			}
+			seen[segment] = true
+			rows = append(rows, model.CustomerSegment{CustomerID: customerID, Segment: segment})
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetCustomerSegments(ctx, customerID)
+}
+
+func (r *customerRepositoryImpl) IsCustomerInSegment(ctx context.Context, customerID, segment string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.CustomerSegment{}).
+		Where("customer_id = ? AND segment = ?", customerID, segment).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
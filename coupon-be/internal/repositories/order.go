@@ -0,0 +1,290 @@
+package repositories
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderRepository interface {
+	CreateOrder(ctx context.Context, order model.Order) (model.Order, error)
+	GetOrderByID(ctx context.Context, id uint64) (model.Order, error)
+	CountRedemptions(ctx context.Context, couponCode string) (int64, error)
+	CountCustomerRedemptions(ctx context.Context, couponCode, customerID string) (int64, error)
+	CountCustomerOrders(ctx context.Context, customerID string) (int64, error)
+	SumCouponDiscounts(ctx context.Context, couponCode string) (money.Amount, error)
+	SumCampaignDiscounts(ctx context.Context, campaignID uint64) (money.Amount, error)
+}
+
+type orderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepositoryImpl{db: db}
+}
+
+func (r *orderRepositoryImpl) CreateOrder(ctx context.Context, order model.Order) (model.Order, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Redemptions are saved together with the order so a coupon is never
+		// recorded as used by an order that failed to persist.
+		if err := checkRedemptionLimits(tx, order); err != nil {
+			return err
+		}
+		return tx.Create(&order).Error
+	})
+	if err != nil {
+		return model.Order{}, err
+	}
+	return order, nil
+}
+
+func (r *orderRepositoryImpl) GetOrderByID(ctx context.Context, id uint64) (model.Order, error) {
+	var order model.Order
+	if err := r.db.WithContext(ctx).Preload("Items").Preload("Redemptions").First(&order, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.Order{}, errs.NotFoundError{Message: fmt.Sprintf("Order with ID %d not found", id)}
+		}
+		return model.Order{}, err
+	}
+	return order, nil
+}
+
+func (r *orderRepositoryImpl) CountRedemptions(ctx context.Context, couponCode string) (int64, error) {
+	return countRedemptions(r.db.WithContext(ctx), couponCode, nil)
+}
+
+func (r *orderRepositoryImpl) CountCustomerRedemptions(ctx context.Context, couponCode, customerID string) (int64, error) {
+	return countRedemptions(r.db.WithContext(ctx), couponCode, &customerID)
+}
+
+func (r *orderRepositoryImpl) CountCustomerOrders(ctx context.Context, customerID string) (int64, error) {
+	return countCustomerOrders(r.db.WithContext(ctx), customerID)
+}
+
+func (r *orderRepositoryImpl) SumCouponDiscounts(ctx context.Context, couponCode string) (money.Amount, error) {
+	return sumCouponDiscounts(r.db.WithContext(ctx), couponCode)
+}
+
+func (r *orderRepositoryImpl) SumCampaignDiscounts(ctx context.Context, campaignID uint64) (money.Amount, error) {
+	return sumCampaignDiscounts(r.db.WithContext(ctx), campaignID)
+}
+
+// checkRedemptionLimits locks every coupon redeemed by the order and re-checks
+// its usage limits, budget and first-order restriction, so concurrent orders
+// cannot both take the last redemption or the last of a budget, or both be a
+// customer's first order. Coupons are locked in code order, then the
+// customer, to avoid deadlocks between transactions.
+func checkRedemptionLimits(tx *gorm.DB, order model.Order) error {
+	codes := make([]string, 0, len(order.Redemptions))
+	discounts := make(map[string]money.Amount, len(order.Redemptions))
+	for _, redemption := range order.Redemptions {
+		codes = append(codes, redemption.CouponCode)
+		discounts[redemption.CouponCode] = redemption.DiscountAmount
+	}
+	sort.Strings(codes)
+
+	var budgetCoupons, firstOrderCoupons []model.Coupon
+	campaignDiscounts := make(map[uint64]money.Amount)
+	for _, code := range codes {
+		var coupon model.Coupon
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, "coupon_code = ?", code).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errs.BadRequestError{Message: "Coupon not found"}
+			}
+			return err
+		}
+		if coupon.MaxRedemptions != nil {
+			total, err := countRedemptions(tx, code, nil)
+			if err != nil {
+				return err
+			}
+			if total >= int64(*coupon.MaxRedemptions) {
+				return errs.BadRequestError{Message: fmt.Sprintf("coupon %s has reached its redemption limit", code)}
+			}
+		}
+		if coupon.MaxRedemptionsPerCustomer != nil {
+			if order.CustomerID == nil {
+				return errs.BadRequestError{Message: fmt.Sprintf("coupon %s requires a customer_id", code)}
+			}
+			used, err := countRedemptions(tx, code, order.CustomerID)
+			if err != nil {
+				return err
+			}
+			if used >= int64(*coupon.MaxRedemptionsPerCustomer) {
+				return errs.BadRequestError{Message: fmt.Sprintf("customer %s has reached the redemption limit of coupon %s", *order.CustomerID, code)}
+			}
+		}
+		if coupon.FirstOrderOnly {
+			if order.CustomerID == nil {
+				return errs.BadRequestError{Message: fmt.Sprintf("coupon %s requires a customer_id", code)}
+			}
+			firstOrderCoupons = append(firstOrderCoupons, coupon)
+		}
+		if coupon.Budget != nil {
+			budgetCoupons = append(budgetCoupons, coupon)
+		}
+		if coupon.CampaignID != nil {
+			campaignDiscounts[*coupon.CampaignID] = discounts[code].Add(campaignDiscounts[*coupon.CampaignID])
+		}
+	}
+	if err := checkFirstOrder(tx, firstOrderCoupons, order.CustomerID); err != nil {
+		return err
+	}
+	if err := checkCouponBudgets(tx, budgetCoupons, discounts); err != nil {
+		return err
+	}
+	return checkCampaignBudgets(tx, campaignDiscounts)
+}
+
+// checkFirstOrder checks that the order is the customer's first if it redeems
+// first-order-only coupons. The customer's lock row is created if missing and
+// locked before counting their orders, so two first orders redeeming different
+// coupons cannot both go through.
+func checkFirstOrder(tx *gorm.DB, coupons []model.Coupon, customerID *string) error {
+	if len(coupons) == 0 {
+		return nil
+	}
+	lock := model.CustomerOrderLock{CustomerID: *customerID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&lock).Error; err != nil {
+		return err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lock, "customer_id = ?", *customerID).Error; err != nil {
+		return err
+	}
+	orders, err := countCustomerOrders(tx, *customerID)
+	if err != nil {
+		return err
+	}
+	if orders > 0 {
+		return errs.BadRequestError{Message: fmt.Sprintf("coupon %s is only valid on a first order and customer %s already has %d orders", coupons[0].CouponCode, *customerID, orders)}
+	}
+	return nil
+}
+
+// checkCouponBudgets checks that the discount the order takes from each
+// coupon budget fits in what is left of it. Codes generated by a batch draw
+// from the budget of their parent, so the parent is locked too, after the
+// order's coupons and in code order, and concurrent orders on sibling codes
+// cannot both take the last of it.
+func checkCouponBudgets(tx *gorm.DB, coupons []model.Coupon, discounts map[string]money.Amount) error {
+	budgetDiscounts := make(map[string]money.Amount, len(coupons))
+	for _, coupon := range coupons {
+		code := coupon.BudgetCode()
+		budgetDiscounts[code] = discounts[coupon.CouponCode].Add(budgetDiscounts[code])
+	}
+	codes := make([]string, 0, len(budgetDiscounts))
+	for code := range budgetDiscounts {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	used := make(map[string]money.Amount, len(codes))
+	for _, code := range codes {
+		// The parent may have been deleted since its codes were generated.
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("coupon_code").First(&model.Coupon{}, "coupon_code = ?", code).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if used[code], err = sumCouponDiscounts(tx, code); err != nil {
+			return err
+		}
+	}
+	for _, coupon := range coupons {
+		code := coupon.BudgetCode()
+		if used[code].Add(budgetDiscounts[code]).GreaterThan(*coupon.Budget) {
+			return errs.BadRequestError{Message: fmt.Sprintf("coupon %s has not enough budget left for a discount of %s", coupon.CouponCode, discounts[coupon.CouponCode].StringFixed())}
+		}
+	}
+	return nil
+}
+
+// checkCampaignBudgets locks the campaigns of the order's coupons and checks
+// that the discount the order takes from each of them fits in its budget.
+// Campaigns are locked after coupons and in ID order, like coupons.
+func checkCampaignBudgets(tx *gorm.DB, discounts map[uint64]money.Amount) error {
+	ids := make([]uint64, 0, len(discounts))
+	for id := range discounts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		var campaign model.Campaign
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&campaign, "id = ?", id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errs.BadRequestError{Message: fmt.Sprintf("Campaign with ID %d not found", id)}
+			}
+			return err
+		}
+		if campaign.Budget == nil {
+			continue
+		}
+		used, err := sumCampaignDiscounts(tx, id)
+		if err != nil {
+			return err
+		}
+		if used.Add(discounts[id]).GreaterThan(*campaign.Budget) {
+			return errs.BadRequestError{Message: fmt.Sprintf("campaign %s has not enough budget left for a discount of %s", campaign.Name, discounts[id].StringFixed())}
+		}
+	}
+	return nil
+}
+
+// sumCouponDiscounts adds up the discount a coupon and the codes generated
+// from it have given so far.
+func sumCouponDiscounts(tx *gorm.DB, couponCode string) (money.Amount, error) {
+	var sum money.Amount
+	err := tx.Model(&model.CouponRedemption{}).
+		Select("COALESCE(SUM(coupon_redemptions.discount_amount), 0)").
+		Joins("JOIN coupons ON coupons.coupon_code = coupon_redemptions.coupon_code").
+		Where("coupons.coupon_code = ? OR coupons.parent_coupon_code = ?", couponCode, couponCode).
+		Row().Scan(&sum)
+	if err != nil {
+		return money.Zero, err
+	}
+	return sum, nil
+}
+
+// sumCampaignDiscounts adds up the discount every coupon of a campaign has
+// given so far.
+func sumCampaignDiscounts(tx *gorm.DB, campaignID uint64) (money.Amount, error) {
+	var sum money.Amount
+	err := tx.Model(&model.CouponRedemption{}).
+		Select("COALESCE(SUM(coupon_redemptions.discount_amount), 0)").
+		Joins("JOIN coupons ON coupons.coupon_code = coupon_redemptions.coupon_code").
+		Where("coupons.campaign_id = ?", campaignID).
+		Row().Scan(&sum)
+	if err != nil {
+		return money.Zero, err
+	}
+	return sum, nil
+}
+
+func countCustomerOrders(tx *gorm.DB, customerID string) (int64, error) {
+	var count int64
+	if err := tx.Model(&model.Order{}).Where("customer_id = ?", customerID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func countRedemptions(tx *gorm.DB, couponCode string, customerID *string) (int64, error) {
+	var count int64
+	query := tx.Model(&model.CouponRedemption{}).Where("coupon_redemptions.coupon_code = ?", couponCode)
+	if customerID != nil {
+		query = query.Joins("JOIN orders ON orders.id = coupon_redemptions.order_id").
+			Where("orders.customer_id = ?", *customerID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
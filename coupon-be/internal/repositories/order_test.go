@@ -0,0 +1,238 @@
+package repositories
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func InitializeOrderRepository(t *testing.T) OrderRepository {
+	db, err := gorm.Open(mysql.Open("root:123123@tcp(localhost:3306)/zalopay?charset=utf8mb4&parseTime=True&loc=Local"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	limit := 1
+	coupons := []model.Coupon{
+		{
+			CouponCode:  "ORDERTEST",
+			Title:       "Order Test Coupon",
+			Description: "Description for Order Test Coupon",
+			CouponType:  model.CouponTypeFixed,
+			Usage:       model.CouponUsageManual,
+			ExpiredAt:   time.Now().AddDate(0, 0, 10),
+			CouponValue: money.NewFromInt(10000),
+		},
+		{
+			CouponCode:     "ORDERTESTLIMIT",
+			Title:          "Order Test Limited Coupon",
+			Description:    "Description for Order Test Limited Coupon",
+			CouponType:     model.CouponTypeFixed,
+			Usage:          model.CouponUsageManual,
+			ExpiredAt:      time.Now().AddDate(0, 0, 10),
+			CouponValue:    money.NewFromInt(10000),
+			MaxRedemptions: &limit,
+		},
+	}
+	if err := db.Create(&coupons).Error; err != nil {
+		t.Fatalf("Failed to seed database: %v", err)
+	}
+	order := model.Order{
+		Cost:           money.NewFromInt(50000),
+		DiscountAmount: money.NewFromInt(10000),
+		TotalAmount:    money.NewFromInt(40000),
+		Currency:       "VND",
+		Redemptions:    []model.CouponRedemption{{CouponCode: "ORDERTESTLIMIT", DiscountAmount: money.NewFromInt(10000)}},
+	}
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("Failed to seed database: %v", err)
+	}
+	return NewOrderRepository(db)
+}
+
+func RemoveOrderSeed(t *testing.T) {
+	db, err := gorm.Open(mysql.Open("root:123123@tcp(localhost:3306)/zalopay?charset=utf8mb4&parseTime=True&loc=Local"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	// Items and redemptions are removed with their order.
+	if err := db.Exec("DELETE FROM orders").Error; err != nil {
+		t.Fatalf("Failed to remove seed data: %v", err)
+	}
+	if err := db.Exec("DELETE FROM coupons").Error; err != nil {
+		t.Fatalf("Failed to remove seed data: %v", err)
+	}
+	if err := db.Exec("DELETE FROM customer_order_locks").Error; err != nil {
+		t.Fatalf("Failed to remove seed data: %v", err)
+	}
+}
+
+func TestCreateOrder(t *testing.T) {
+	repo := InitializeOrderRepository(t)
+	customerID := "customer-1"
+	type args struct {
+		ctx   context.Context
+		order model.Order
+	}
+	type want struct {
+		cost money.Amount
+		err  error
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{
+			name: "Create order above 100,000,000 VND with items and a redemption",
+			args: args{
+				ctx: context.Background(),
+				order: model.Order{
+					CustomerID:     &customerID,
+					Cost:           money.NewFromInt(150000000),
+					DiscountAmount: money.NewFromInt(10000),
+					TotalAmount:    money.NewFromInt(149990000),
+					Currency:       "VND",
+					Items: []model.OrderItem{{
+						SKU:            "SKU-1",
+						Quantity:       3,
+						UnitPrice:      money.NewFromInt(50000000),
+						DiscountAmount: money.NewFromInt(10000),
+						TotalAmount:    money.NewFromInt(149990000),
+					}},
+					Redemptions: []model.CouponRedemption{{CouponCode: "ORDERTEST", DiscountAmount: money.NewFromInt(10000)}},
+				},
+			},
+			want: want{
+				cost: money.NewFromInt(150000000),
+				err:  nil,
+			},
+		},
+		{
+			name: "Create order redeeming a coupon at its redemption limit",
+			args: args{
+				ctx: context.Background(),
+				order: model.Order{
+					Cost:           money.NewFromInt(50000),
+					DiscountAmount: money.NewFromInt(10000),
+					TotalAmount:    money.NewFromInt(40000),
+					Currency:       "VND",
+					Redemptions:    []model.CouponRedemption{{CouponCode: "ORDERTESTLIMIT", DiscountAmount: money.NewFromInt(10000)}},
+				},
+			},
+			want: want{
+				err: errs.BadRequestError{Message: "coupon ORDERTESTLIMIT has reached its redemption limit"},
+			},
+		},
+		{
+			name: "Create order redeeming an unknown coupon",
+			args: args{
+				ctx: context.Background(),
+				order: model.Order{
+					Cost:           money.NewFromInt(50000),
+					DiscountAmount: money.NewFromInt(10000),
+					TotalAmount:    money.NewFromInt(40000),
+					Currency:       "VND",
+					Redemptions:    []model.CouponRedemption{{CouponCode: "INVALID_ID", DiscountAmount: money.NewFromInt(10000)}},
+				},
+			},
+			want: want{
+				err: errs.BadRequestError{Message: "Coupon not found"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := repo.CreateOrder(tt.args.ctx, tt.args.order)
+			if (err == nil) != (tt.want.err == nil) || err != nil && err.Error() != tt.want.err.Error() {
+				t.Fatalf("CreateOrder(), test name: %s, error = %v, wantErr %v", tt.name, err, tt.want.err)
+			}
+			if err != nil {
+				return
+			}
+			stored, err := repo.GetOrderByID(tt.args.ctx, order.ID)
+			if err != nil {
+				t.Fatalf("GetOrderByID(), test name: %s, error = %v", tt.name, err)
+			}
+			if !stored.Cost.Equal(tt.want.cost) {
+				t.Errorf("CreateOrder(), test name: %s, cost = %v, want %v", tt.name, stored.Cost, tt.want.cost)
+			}
+			if len(stored.Items) != len(tt.args.order.Items) || len(stored.Redemptions) != len(tt.args.order.Redemptions) {
+				t.Errorf("CreateOrder(), test name: %s, items = %d, redemptions = %d, want %d and %d", tt.name, len(stored.Items), len(stored.Redemptions), len(tt.args.order.Items), len(tt.args.order.Redemptions))
+			}
+		})
+	}
+	RemoveOrderSeed(t)
+}
+
+func TestGetOrderByID(t *testing.T) {
+	repo := InitializeOrderRepository(t)
+	_, err := repo.GetOrderByID(context.Background(), 0)
+	want := errs.NotFoundError{Message: "Order with ID 0 not found"}
+	if err == nil || err.Error() != want.Error() {
+		t.Errorf("GetOrderByID(), error = %v, wantErr %v", err, want)
+	}
+	RemoveOrderSeed(t)
+}
+
+func TestCreateOrderConcurrentFirstOrders(t *testing.T) {
+	repo := InitializeOrderRepository(t)
+	db, err := gorm.Open(mysql.Open("root:123123@tcp(localhost:3306)/zalopay?charset=utf8mb4&parseTime=True&loc=Local"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	codes := []string{"FIRSTORDER1", "FIRSTORDER2"}
+	for _, code := range codes {
+		coupon := model.Coupon{
+			CouponCode:     code,
+			Title:          "First Order Coupon",
+			Description:    "Description for First Order Coupon",
+			CouponType:     model.CouponTypeFixed,
+			Usage:          model.CouponUsageManual,
+			ExpiredAt:      time.Now().AddDate(0, 0, 10),
+			CouponValue:    money.NewFromInt(10000),
+			FirstOrderOnly: true,
+		}
+		if err := db.Create(&coupon).Error; err != nil {
+			t.Fatalf("Failed to seed database: %v", err)
+		}
+	}
+
+	customerID := "first-order-customer"
+	orderErrs := make([]error, len(codes))
+	var wg sync.WaitGroup
+	for i, code := range codes {
+		wg.Add(1)
+		go func(i int, code string) {
+			defer wg.Done()
+			_, orderErrs[i] = repo.CreateOrder(context.Background(), model.Order{
+				CustomerID:     &customerID,
+				Cost:           money.NewFromInt(50000),
+				DiscountAmount: money.NewFromInt(10000),
+				TotalAmount:    money.NewFromInt(40000),
+				Currency:       "VND",
+				Redemptions:    []model.CouponRedemption{{CouponCode: code, DiscountAmount: money.NewFromInt(10000)}},
+			})
+		}(i, code)
+	}
+	wg.Wait()
+
+	created := 0
+	for i, err := range orderErrs {
+		if err == nil {
+			created++
+		} else if _, ok := err.(errs.BadRequestError); !ok {
+			t.Errorf("CreateOrder() with coupon %s, error = %v", codes[i], err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("CreateOrder() created %d first orders for one customer, want 1: %v", created, orderErrs)
+	}
+	RemoveOrderSeed(t)
+}
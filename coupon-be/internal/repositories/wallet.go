@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WalletRepository interface {
+	ClaimCoupon(ctx context.Context, wallet model.WalletCoupon) (model.WalletCoupon, error)
+	GetWalletCoupons(ctx context.Context, customerID string) ([]model.WalletCoupon, error)
+	GetWalletCoupon(ctx context.Context, customerID, couponCode string) (model.WalletCoupon, error)
+	CountWalletRedemptions(ctx context.Context, customerID string) (map[string]int64, error)
+	ClaimLimitedCoupon(ctx context.Context, wallet model.WalletCoupon) (bool, error)
+	GetCouponClaimants(ctx context.Context, couponCode string) ([]string, error)
+}
+
+type walletRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWalletRepository(db *gorm.DB) WalletRepository {
+	return &walletRepositoryImpl{db: db}
+}
+
+func (r *walletRepositoryImpl) ClaimCoupon(ctx context.Context, wallet model.WalletCoupon) (model.WalletCoupon, error) {
+	if err := r.db.WithContext(ctx).Omit("Coupon").Create(&wallet).Error; err != nil {
+		if mysqlErr, ok := err.(*mysql.MySQLError); ok {
+			if mysqlErr.Number == 1062 { // Duplicate entry error code
+				return model.WalletCoupon{}, errs.BadRequestError{Message: fmt.Sprintf("Customer %s already claimed coupon %s", wallet.CustomerID, wallet.CouponCode)}
+			}
+		}
+		return model.WalletCoupon{}, err
+	}
+	return wallet, nil
+}
+
+// GetWalletCoupons returns every coupon the customer claimed, latest claim
+// first, leaving out coupons deleted since.
+func (r *walletRepositoryImpl) GetWalletCoupons(ctx context.Context, customerID string) ([]model.WalletCoupon, error) {
+	var wallet []model.WalletCoupon
+	err := r.db.WithContext(ctx).
+		InnerJoins("Coupon").
+		Where("wallet_coupons.customer_id = ?", customerID).
+		Order("wallet_coupons.claimed_at DESC, wallet_coupons.id DESC").
+		Find(&wallet).Error
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+func (r *walletRepositoryImpl) GetWalletCoupon(ctx context.Context, customerID, couponCode string) (model.WalletCoupon, error) {
+	var wallet model.WalletCoupon
+	err := r.db.WithContext(ctx).
+		Where("customer_id = ? AND coupon_code = ?", customerID, couponCode).
+		First(&wallet).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return model.WalletCoupon{}, errs.NotFoundError{Message: fmt.Sprintf("Coupon %s is not in the wallet of customer %s", couponCode, customerID)}
+		}
+		return model.WalletCoupon{}, err
+	}
+	return wallet, nil
+}
+
+// CountWalletRedemptions counts the customer's redemptions of each coupon in
+// their wallet.
+func (r *walletRepositoryImpl) CountWalletRedemptions(ctx context.Context, customerID string) (map[string]int64, error) {
+	var rows []struct {
+		CouponCode string
+		Count      int64
+	}
+	err := r.db.WithContext(ctx).Model(&model.CouponRedemption{}).
+		Select("coupon_redemptions.coupon_code, COUNT(*) AS count").
+		Joins("JOIN orders ON orders.id = coupon_redemptions.order_id").
+		Joins("JOIN wallet_coupons ON wallet_coupons.coupon_code = coupon_redemptions.coupon_code AND wallet_coupons.customer_id = orders.customer_id").
+		Where("orders.customer_id = ?", customerID).
+		Group("coupon_redemptions.coupon_code").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CouponCode] = row.Count
+	}
+	return counts, nil
+}
+
+// ClaimLimitedCoupon adds a claim on a coupon whose claims may be limited. The
+// coupon is locked while its claims are counted, so claims never go over its
+// ClaimLimit however they reach the database. It reports false if the claims
+// are sold out or the coupon is gone. A claim the customer already holds
+// counts as claimed, so the same claim can safely be persisted twice.
+func (r *walletRepositoryImpl) ClaimLimitedCoupon(ctx context.Context, wallet model.WalletCoupon) (bool, error) {
+	claimed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var coupon model.Coupon
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, "coupon_code = ?", wallet.CouponCode).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+		var held int64
+		if err := tx.Model(&model.WalletCoupon{}).Where("customer_id = ? AND coupon_code = ?", wallet.CustomerID, wallet.CouponCode).Count(&held).Error; err != nil {
+			return err
+		}
+		if held > 0 {
+			claimed = true
+			return nil
+		}
+		if coupon.ClaimLimit != nil {
+			var total int64
+			if err := tx.Model(&model.WalletCoupon{}).Where("coupon_code = ?", wallet.CouponCode).Count(&total).Error; err != nil {
+				return err
+			}
+			if total >= int64(*coupon.ClaimLimit) {
+				return nil
+			}
+		}
+		if err := tx.Omit("Coupon").Create(&wallet).Error; err != nil {
+			return err
+		}
+		claimed = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+// GetCouponClaimants returns the customers holding a claim on the coupon.
+func (r *walletRepositoryImpl) GetCouponClaimants(ctx context.Context, couponCode string) ([]string, error) {
+	customerIDs := []string{}
+	err := r.db.WithContext(ctx).Model(&model.WalletCoupon{}).
+		Where("coupon_code = ?", couponCode).
+		Pluck("customer_id", &customerIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return customerIDs, nil
+}
@@ -36,6 +36,11 @@ func NewRouter(handler *gin.Engine,
 	l logger.Interface,
 	couponController controller.CouponController,
 	orderController controller.OrderController,
+	customerController controller.CustomerController,
+	couponBatchController controller.CouponBatchController,
+	campaignController controller.CampaignController,
+	walletController controller.WalletController,
+	flashClaimController controller.FlashClaimController,
 ) {
 	// Options
 	handler.Use(gin.Logger())
@@ -50,7 +55,7 @@ func NewRouter(handler *gin.Engine,
 	// Routers
 	h := handler.Group("/api")
 	{
-		v1Router.NewRouter(h, l, couponController, orderController)
+		v1Router.NewRouter(h, l, couponController, orderController, customerController, couponBatchController, campaignController, walletController, flashClaimController)
 	}
 
 }
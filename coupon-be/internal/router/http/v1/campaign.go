@@ -0,0 +1,240 @@
+package router
+
+import (
+	"coupon-be/internal/controller"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+	"coupon-be/utils"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CampaignRoutes struct {
+	l                  logger.Interface
+	campaignController controller.CampaignController
+}
+
+func NewCampaignRoutes(handler *gin.RouterGroup, l logger.Interface, campaignController controller.CampaignController) {
+	r := &CampaignRoutes{l, campaignController}
+	h := handler.Group("/campaigns")
+	{
+		h.POST("", r.CreateCampaign)
+		h.GET("", r.GetCampaigns)
+		h.GET("/:id", r.GetCampaignByID)
+		h.PUT("/:id", r.UpdateCampaign)
+		h.DELETE("/:id", r.DeleteCampaign)
+		h.GET("/:id/stats", r.GetCampaignStats)
+	}
+}
+
+// @Summary     Create a campaign
+// @Description Create a campaign to group coupons under one budget, date range and owner
+// @ID          createCampaign
+// @Tags        Campaigns
+// @Accept      json
+// @Produce     json
+// @Param       campaign body schema.CreateCampaignRequest true "Campaign data"
+// @Success     200 {object} schema.Response[schema.CampaignResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/campaigns [post]
+func (r *CampaignRoutes) CreateCampaign(c *gin.Context) {
+	var req schema.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error("Failed to bind JSON for CreateCampaign", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	campaign, err := r.campaignController.CreateCampaign(c.Request.Context(), req)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CampaignResponse]{
+		Data:    schema.ToCampaignResponse(campaign),
+		Message: "Campaign created successfully",
+		Code:    200,
+	})
+}
+
+// @Summary     Get all campaigns
+// @Description Get all campaigns with pagination, newest first
+// @ID          getCampaigns
+// @Tags        Campaigns
+// @Accept      json
+// @Produce     json
+// @Param       offset query int false "Offset for pagination"
+// @Param       limit query int false "Limit for pagination"
+// @Success     200 {object} schema.PaginationResponse[schema.CampaignResponse]
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/campaigns [get]
+func (r *CampaignRoutes) GetCampaigns(c *gin.Context) {
+	offset, limit, err := utils.GetPaginationParams(c)
+	if err != nil {
+		r.l.Error("Failed to parse pagination parameters", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid pagination parameters"})
+		return
+	}
+	campaigns, total, err := r.campaignController.GetCampaignsWithTotal(c.Request.Context(), offset, limit)
+	if err != nil {
+		r.l.Error("Failed to get campaigns", "error", err)
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.PaginationResponse[schema.CampaignResponse]{
+		Data:    schema.ToCampaignResponses(campaigns),
+		Message: "Campaigns retrieved successfully",
+		Paging: schema.Paging{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	})
+}
+
+// @Summary     Get a campaign by ID
+// @Description Get a campaign by its ID
+// @ID          getCampaignByID
+// @Tags        Campaigns
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Campaign ID"
+// @Success     200 {object} schema.Response[schema.CampaignResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/campaigns/{id} [get]
+func (r *CampaignRoutes) GetCampaignByID(c *gin.Context) {
+	id, ok := campaignID(c)
+	if !ok {
+		return
+	}
+
+	campaign, err := r.campaignController.GetCampaignByID(c.Request.Context(), id)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CampaignResponse]{
+		Data:    schema.ToCampaignResponse(campaign),
+		Message: "Campaign retrieved successfully",
+		Code:    200,
+	})
+}
+
+// @Summary     Update a campaign
+// @Description Update the fields set in the request and leave the others unchanged
+// @ID          updateCampaign
+// @Tags        Campaigns
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Campaign ID"
+// @Param       campaign body schema.UpdateCampaignRequest true "Updated campaign data"
+// @Success     200 {object} schema.Response[schema.CampaignResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/campaigns/{id} [put]
+func (r *CampaignRoutes) UpdateCampaign(c *gin.Context) {
+	id, ok := campaignID(c)
+	if !ok {
+		return
+	}
+	var req schema.UpdateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error("Failed to bind JSON for UpdateCampaign", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	campaign, err := r.campaignController.UpdateCampaign(c.Request.Context(), id, req)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CampaignResponse]{
+		Data:    schema.ToCampaignResponse(campaign),
+		Message: "Campaign updated successfully",
+		Code:    200,
+	})
+}
+
+// @Summary     Delete a campaign
+// @Description Delete a campaign that no longer has coupons
+// @ID          deleteCampaign
+// @Tags        Campaigns
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Campaign ID"
+// @Success     200 {object} schema.Response[string]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/campaigns/{id} [delete]
+func (r *CampaignRoutes) DeleteCampaign(c *gin.Context) {
+	id, ok := campaignID(c)
+	if !ok {
+		return
+	}
+
+	if err := r.campaignController.DeleteCampaign(c.Request.Context(), id); err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[string]{
+		Message: "Campaign deleted successfully",
+		Data:    fmt.Sprintf("Campaign with ID %d has been deleted", id),
+		Code:    200,
+	})
+}
+
+// @Summary     Get campaign stats
+// @Description Aggregate the redemptions of every coupon of a campaign
+// @ID          getCampaignStats
+// @Tags        Campaigns
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Campaign ID"
+// @Success     200 {object} schema.Response[schema.CampaignStatsResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/campaigns/{id}/stats [get]
+func (r *CampaignRoutes) GetCampaignStats(c *gin.Context) {
+	id, ok := campaignID(c)
+	if !ok {
+		return
+	}
+
+	stats, err := r.campaignController.GetCampaignStats(c.Request.Context(), id)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CampaignStatsResponse]{
+		Data:    stats,
+		Message: "Campaign stats retrieved successfully",
+		Code:    200,
+	})
+}
+
+// campaignID parses the campaign ID path parameter, writing an error response
+// if it is invalid.
+func campaignID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid campaign ID"})
+		return 0, false
+	}
+	return id, true
+}
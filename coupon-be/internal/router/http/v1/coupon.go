@@ -2,6 +2,7 @@ package router
 
 import (
 	"coupon-be/internal/controller"
+	"coupon-be/internal/model"
 	"coupon-be/internal/schema"
 	"coupon-be/pkg/logger"
 	"coupon-be/pkg/utils/errs"
@@ -20,10 +21,16 @@ func NewCouponRoutes(handler *gin.RouterGroup, l logger.Interface, couponControl
 	h := handler.Group("/coupons")
 	{
 		h.POST("", r.CreateCoupon)
+		h.POST("/recommendations", r.RecommendCoupons)
 		h.GET("", r.GetCoupons)
+		h.GET("/deleted", r.GetDeletedCoupons)
 		h.GET("/:id", r.GetCouponByID)
 		h.PUT("/:id", r.UpdateCoupon)
 		h.DELETE("/:id", r.DeleteCoupon)
+		h.POST("/:id/pause", r.PauseCoupon)
+		h.POST("/:id/resume", r.ResumeCoupon)
+		h.POST("/:id/archive", r.ArchiveCoupon)
+		h.POST("/:id/restore", r.RestoreCoupon)
 	}
 }
 
@@ -69,6 +76,8 @@ func (r *CouponRoutes) CreateCoupon(c *gin.Context) {
 // @Param       offset query int false "Offset for pagination"
 // @Param       limit query int false "Limit for pagination"
 // @Param	    coupon_code query string false "Filter by coupon code"
+// @Param       channel query string false "Only coupons usable on this channel"
+// @Param       payment_method query string false "Only coupons usable with this payment method"
 // @Success     200 {object} schema.PaginationResponse[schema.CouponResponse]
 // @Failure     500 {object} schema.ErrorResponse
 // @Router      /v1/coupons [get]
@@ -80,7 +89,9 @@ func (r *CouponRoutes) GetCoupons(c *gin.Context) {
 		return
 	}
 	couponCode := c.Query("coupon_code")
-	coupons, total, err := r.couponController.GetCouponsWithTotal(c.Request.Context(), offset, limit, couponCode)
+	channel := c.Query("channel")
+	paymentMethod := c.Query("payment_method")
+	coupons, total, err := r.couponController.GetCouponsWithTotal(c.Request.Context(), offset, limit, couponCode, channel, paymentMethod)
 	if err != nil {
 		r.l.Error("Failed to get coupons", "error", err)
 		schema.NewErrorResponse(c, err)
@@ -200,3 +211,173 @@ func (r *CouponRoutes) DeleteCoupon(c *gin.Context) {
 		Code:    200,
 	})
 }
+
+// @Summary     Recommend coupons for an order
+// @Description Evaluate every eligible coupon for an order and return the 20 that save the most, ranked by savings
+// @ID          recommendCoupons
+// @Tags        Coupons
+// @Accept      json
+// @Produce     json
+// @Param       order body schema.CreateMockOrderRequest true "Order data"
+// @Success     200 {object} schema.Response[[]schema.CouponRecommendationResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/recommendations [post]
+func (r *CouponRoutes) RecommendCoupons(c *gin.Context) {
+	var req schema.CreateMockOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error("Failed to bind JSON for RecommendCoupons", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	recommendations, err := r.couponController.RecommendCoupons(c.Request.Context(), req)
+	if err != nil {
+		r.l.Error("Failed to recommend coupons", "error", err)
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[[]schema.CouponRecommendationResponse]{
+		Data:    recommendations,
+		Message: "Coupon recommendations retrieved successfully",
+		Code:    200,
+	})
+}
+
+// @Summary     Get deleted coupons
+// @Description Get soft-deleted coupons with pagination
+// @ID          getDeletedCoupons
+// @Tags        Coupons
+// @Accept      json
+// @Produce     json
+// @Param       offset query int false "Offset for pagination"
+// @Param       limit query int false "Limit for pagination"
+// @Success     200 {object} schema.PaginationResponse[schema.CouponResponse]
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/deleted [get]
+func (r *CouponRoutes) GetDeletedCoupons(c *gin.Context) {
+	offset, limit, err := utils.GetPaginationParams(c)
+	if err != nil {
+		r.l.Error("Failed to parse pagination parameters", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid pagination parameters"})
+		return
+	}
+	coupons, total, err := r.couponController.GetDeletedCouponsWithTotal(c.Request.Context(), offset, limit)
+	if err != nil {
+		r.l.Error("Failed to get deleted coupons", "error", err)
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.PaginationResponse[schema.CouponResponse]{
+		Data:    schema.ToCouponResponses(coupons),
+		Message: "Deleted coupons retrieved successfully",
+		Paging: schema.Paging{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	})
+}
+
+// @Summary     Restore a coupon
+// @Description Restore a soft-deleted coupon by its ID
+// @ID          restoreCoupon
+// @Tags        Coupons
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Coupon ID"
+// @Success     200 {object} schema.Response[schema.CouponResponse]
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/{id}/restore [post]
+func (r *CouponRoutes) RestoreCoupon(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Coupon ID is required"})
+		return
+	}
+
+	coupon, err := r.couponController.RestoreCoupon(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error("Failed to restore coupon", "error", err)
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CouponResponse]{
+		Data:    schema.ToCouponResponse(coupon),
+		Message: "Coupon restored successfully",
+		Code:    200,
+	})
+}
+
+// @Summary     Pause a coupon
+// @Description Temporarily stop a coupon from being redeemed
+// @ID          pauseCoupon
+// @Tags        Coupons
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Coupon ID"
+// @Success     200 {object} schema.Response[schema.CouponResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/{id}/pause [post]
+func (r *CouponRoutes) PauseCoupon(c *gin.Context) {
+	r.changeCouponStatus(c, model.CouponStatusPaused, "Coupon paused successfully")
+}
+
+// @Summary     Resume a coupon
+// @Description Make a draft or paused coupon redeemable again
+// @ID          resumeCoupon
+// @Tags        Coupons
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Coupon ID"
+// @Success     200 {object} schema.Response[schema.CouponResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/{id}/resume [post]
+func (r *CouponRoutes) ResumeCoupon(c *gin.Context) {
+	r.changeCouponStatus(c, model.CouponStatusActive, "Coupon resumed successfully")
+}
+
+// @Summary     Archive a coupon
+// @Description Permanently retire a coupon while keeping its history
+// @ID          archiveCoupon
+// @Tags        Coupons
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Coupon ID"
+// @Success     200 {object} schema.Response[schema.CouponResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/{id}/archive [post]
+func (r *CouponRoutes) ArchiveCoupon(c *gin.Context) {
+	r.changeCouponStatus(c, model.CouponStatusArchived, "Coupon archived successfully")
+}
+
+func (r *CouponRoutes) changeCouponStatus(c *gin.Context, status model.CouponStatus, message string) {
+	id := c.Param("id")
+	if id == "" {
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Coupon ID is required"})
+		return
+	}
+
+	coupon, err := r.couponController.ChangeCouponStatus(c.Request.Context(), id, status)
+	if err != nil {
+		r.l.Error("Failed to change coupon status", "error", err, "status", status)
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CouponResponse]{
+		Data:    schema.ToCouponResponse(coupon),
+		Message: message,
+		Code:    200,
+	})
+}
@@ -0,0 +1,139 @@
+package router
+
+import (
+	"bytes"
+	"coupon-be/internal/controller"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CouponBatchRoutes struct {
+	l                     logger.Interface
+	couponBatchController controller.CouponBatchController
+}
+
+func NewCouponBatchRoutes(handler *gin.RouterGroup, l logger.Interface, couponBatchController controller.CouponBatchController) {
+	r := &CouponBatchRoutes{l, couponBatchController}
+	handler.POST("/coupons/:id/batches", r.CreateCouponBatch)
+	h := handler.Group("/coupon-batches")
+	{
+		h.GET("/:id", r.GetCouponBatch)
+		h.GET("/:id/download", r.DownloadCouponBatch)
+	}
+}
+
+// CreateCouponBatch godoc
+// @Summary     Generate single-use codes for a coupon
+// @Description Start generating unique single-use codes that copy the offer of the parent coupon. Codes are generated in the background; poll the batch until it is completed, then download them
+// @ID          createCouponBatch
+// @Tags        Coupon Batches
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Parent coupon code"
+// @Param       batch body schema.CreateCouponBatchRequest true "Code template"
+// @Success     200 {object} schema.Response[schema.CouponBatchResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/{id}/batches [post]
+func (r *CouponBatchRoutes) CreateCouponBatch(c *gin.Context) {
+	var req schema.CreateCouponBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error("Failed to bind JSON for CreateCouponBatch", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	batch, err := r.couponBatchController.CreateCouponBatch(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CouponBatchResponse]{
+		Data:    schema.ToCouponBatchResponse(batch),
+		Message: "Coupon batch started successfully",
+		Code:    200,
+	})
+}
+
+// GetCouponBatch godoc
+// @Summary     Get a coupon batch
+// @Description Get the status and progress of a coupon batch
+// @ID          getCouponBatch
+// @Tags        Coupon Batches
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Batch ID"
+// @Success     200 {object} schema.Response[schema.CouponBatchResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupon-batches/{id} [get]
+func (r *CouponBatchRoutes) GetCouponBatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid batch ID"})
+		return
+	}
+
+	batch, err := r.couponBatchController.GetCouponBatch(c.Request.Context(), id)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CouponBatchResponse]{
+		Data:    schema.ToCouponBatchResponse(batch),
+		Message: "Coupon batch retrieved successfully",
+		Code:    200,
+	})
+}
+
+// DownloadCouponBatch godoc
+// @Summary     Download the codes of a coupon batch
+// @Description Download the codes of a completed coupon batch as CSV
+// @ID          downloadCouponBatch
+// @Tags        Coupon Batches
+// @Produce     text/csv
+// @Param       id path int true "Batch ID"
+// @Success     200 {string} string "CSV with a coupon_code column"
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupon-batches/{id}/download [get]
+func (r *CouponBatchRoutes) DownloadCouponBatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid batch ID"})
+		return
+	}
+
+	codes, err := r.couponBatchController.GetCouponBatchCodes(c.Request.Context(), id)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	// Custom alphabets may contain commas and quotes, which the writer quotes.
+	w.Write([]string{"coupon_code"})
+	for _, code := range codes {
+		w.Write([]string{code})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		r.l.Error("Failed to write coupon batch CSV", "error", err, "batch_id", id)
+		schema.NewErrorResponse(c, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=coupon-batch-%d.csv", id))
+	c.Data(200, "text/csv; charset=utf-8", b.Bytes())
+}
@@ -0,0 +1,55 @@
+package router
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeCouponBatchController returns the codes of a completed batch.
+type fakeCouponBatchController struct {
+	codes []string
+}
+
+func (f *fakeCouponBatchController) CreateCouponBatch(_ context.Context, _ string, _ schema.CreateCouponBatchRequest) (model.CouponBatch, error) {
+	return model.CouponBatch{}, nil
+}
+
+func (f *fakeCouponBatchController) GetCouponBatch(_ context.Context, id uint64) (model.CouponBatch, error) {
+	return model.CouponBatch{ID: id}, nil
+}
+
+func (f *fakeCouponBatchController) GetCouponBatchCodes(_ context.Context, _ uint64) ([]string, error) {
+	return f.codes, nil
+}
+
+func (f *fakeCouponBatchController) ResumeCouponBatches(_ context.Context) {}
+
+func TestDownloadCouponBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	codes := []string{"SALE,A1", `SALE"B2`, "SALE C3"}
+	engine := gin.New()
+	NewCouponBatchRoutes(engine.Group("/v1"), logger.New("test"), &fakeCouponBatchController{codes: codes})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/coupon-batches/1/download", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("read CSV: %v", err)
+	}
+	want := [][]string{{"coupon_code"}, {codes[0]}, {codes[1]}, {codes[2]}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("CSV records = %q, want %q", records, want)
+	}
+}
@@ -0,0 +1,83 @@
+package router
+
+import (
+	"coupon-be/internal/controller"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CustomerRoutes struct {
+	l                  logger.Interface
+	customerController controller.CustomerController
+}
+
+func NewCustomerRoutes(handler *gin.RouterGroup, l logger.Interface, customerController controller.CustomerController) {
+	r := &CustomerRoutes{l, customerController}
+	h := handler.Group("/customers")
+	{
+		h.GET("/:id/segments", r.GetCustomerSegments)
+		h.PUT("/:id/segments", r.UpdateCustomerSegments)
+	}
+}
+
+// GetCustomerSegments godoc
+// @Summary     Get the segments of a customer
+// @Description Get the segments a customer belongs to, used by segment-restricted coupons
+// @ID          getCustomerSegments
+// @Tags        Customers
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Customer ID"
+// @Success     200 {object} schema.Response[schema.CustomerSegmentsResponse]
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/customers/{id}/segments [get]
+func (r *CustomerRoutes) GetCustomerSegments(c *gin.Context) {
+	segments, err := r.customerController.GetCustomerSegments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CustomerSegmentsResponse]{
+		Data:    segments,
+		Message: "Customer segments retrieved successfully",
+		Code:    200,
+	})
+}
+
+// UpdateCustomerSegments godoc
+// @Summary     Replace the segments of a customer
+// @Description Replace every segment a customer belongs to. An empty list removes the customer from all segments
+// @ID          updateCustomerSegments
+// @Tags        Customers
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Customer ID"
+// @Param       segments body schema.UpdateCustomerSegmentsRequest true "Customer segments"
+// @Success     200 {object} schema.Response[schema.CustomerSegmentsResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/customers/{id}/segments [put]
+func (r *CustomerRoutes) UpdateCustomerSegments(c *gin.Context) {
+	var req schema.UpdateCustomerSegmentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error("Failed to bind JSON for UpdateCustomerSegments", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	segments, err := r.customerController.UpdateCustomerSegments(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.CustomerSegmentsResponse]{
+		Data:    segments,
+		Message: "Customer segments updated successfully",
+		Code:    200,
+	})
+}
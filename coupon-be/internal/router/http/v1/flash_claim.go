@@ -0,0 +1,86 @@
+package router
+
+import (
+	"coupon-be/internal/controller"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FlashClaimRoutes struct {
+	l                    logger.Interface
+	flashClaimController controller.FlashClaimController
+}
+
+func NewFlashClaimRoutes(handler *gin.RouterGroup, l logger.Interface, flashClaimController controller.FlashClaimController) {
+	r := &FlashClaimRoutes{l, flashClaimController}
+	h := handler.Group("/coupons")
+	{
+		h.POST("/:id/flash-claims", r.ClaimFlashSaleCoupon)
+		h.GET("/:id/flash-sale", r.GetFlashSale)
+	}
+}
+
+// ClaimFlashSaleCoupon godoc
+// @Summary     Claim a flash sale coupon
+// @Description Claim a coupon with a limited number of claims. The claim is taken from the stock at once and added to the customer's wallet shortly after
+// @ID          claimFlashSaleCoupon
+// @Tags        Wallets
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Coupon code"
+// @Param       claim body schema.FlashClaimRequest true "Customer claiming the coupon"
+// @Success     200 {object} schema.Response[schema.FlashClaimResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/{id}/flash-claims [post]
+func (r *FlashClaimRoutes) ClaimFlashSaleCoupon(c *gin.Context) {
+	var req schema.FlashClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error("Failed to bind JSON for ClaimFlashSaleCoupon", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	claim, err := r.flashClaimController.ClaimCoupon(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.FlashClaimResponse]{
+		Data:    claim,
+		Message: "Coupon claimed successfully",
+		Code:    200,
+	})
+}
+
+// GetFlashSale godoc
+// @Summary     Get the stock of a flash sale
+// @Description Get the claim limit of a coupon with the claims remaining, persisted and still pending
+// @ID          getFlashSale
+// @Tags        Wallets
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Coupon code"
+// @Success     200 {object} schema.Response[schema.FlashSaleResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/coupons/{id}/flash-sale [get]
+func (r *FlashClaimRoutes) GetFlashSale(c *gin.Context) {
+	sale, err := r.flashClaimController.GetFlashSale(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.FlashSaleResponse]{
+		Data:    sale,
+		Message: "Flash sale retrieved successfully",
+		Code:    200,
+	})
+}
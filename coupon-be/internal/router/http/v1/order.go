@@ -5,6 +5,7 @@ import (
 	"coupon-be/internal/schema"
 	"coupon-be/pkg/logger"
 	"coupon-be/pkg/utils/errs"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,13 +19,15 @@ func NewOrderRoutes(handler *gin.RouterGroup, l logger.Interface, orderControlle
 	r := &OrderRoutes{l, orderController}
 	h := handler.Group("/orders")
 	{
+		h.POST("", r.CreateOrder)
+		h.GET("/:id", r.GetOrderByID)
 		h.POST("/mock", r.CreateMockOrder)
 	}
 }
 
 // CreateMockOrder godoc
 // @Summary     Create a mock order
-// @Description Create a mock order with optional coupon code
+// @Description Create a mock order with optional coupon code. Without a code the best eligible auto coupon is applied
 // @ID          createMockOrder
 // @Tags        Orders
 // @Accept      json
@@ -54,3 +57,70 @@ func (r *OrderRoutes) CreateMockOrder(c *gin.Context) {
 		Code:    200,
 	})
 }
+
+// CreateOrder godoc
+// @Summary     Create an order
+// @Description Create an order with optional coupon code and record the coupon redemption. Without a code the best eligible auto coupon is applied
+// @ID          createOrder
+// @Tags        Orders
+// @Accept      json
+// @Produce     json
+// @Param       order body schema.CreateMockOrderRequest true "Order data"
+// @Success     200 {object} schema.Response[schema.OrderResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/orders [post]
+func (r *OrderRoutes) CreateOrder(c *gin.Context) {
+	var req schema.CreateMockOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error("Failed to bind JSON for CreateOrder", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	order, err := r.orderController.CreateOrder(c.Request.Context(), req)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.OrderResponse]{
+		Data:    order,
+		Message: "Order created successfully",
+		Code:    200,
+	})
+}
+
+// GetOrderByID godoc
+// @Summary     Get an order by ID
+// @Description Get an order and the coupons redeemed on it
+// @ID          getOrderByID
+// @Tags        Orders
+// @Accept      json
+// @Produce     json
+// @Param       id path int true "Order ID"
+// @Success     200 {object} schema.Response[schema.OrderResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/orders/{id} [get]
+func (r *OrderRoutes) GetOrderByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid order ID"})
+		return
+	}
+
+	order, err := r.orderController.GetOrderByID(c.Request.Context(), id)
+	if err != nil {
+		r.l.Error("Failed to get order by ID", "error", err)
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.OrderResponse]{
+		Data:    schema.ToOrderResponse(order),
+		Message: "Order retrieved successfully",
+		Code:    200,
+	})
+}
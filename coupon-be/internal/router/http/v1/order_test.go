@@ -0,0 +1,173 @@
+package router
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeOrderController records the order it was asked to create and returns
+// the canned order or error.
+type fakeOrderController struct {
+	order model.Order
+	err   error
+	req   *schema.CreateMockOrderRequest
+}
+
+func (f *fakeOrderController) CreateMockOrder(_ context.Context, req schema.CreateMockOrderRequest) (schema.CreateMockOrderResponse, error) {
+	f.req = &req
+	return schema.CreateMockOrderResponse{Cost: f.order.Cost, TotalAmount: f.order.TotalAmount}, f.err
+}
+
+func (f *fakeOrderController) CreateOrder(_ context.Context, req schema.CreateMockOrderRequest) (schema.OrderResponse, error) {
+	f.req = &req
+	if f.err != nil {
+		return schema.OrderResponse{}, f.err
+	}
+	return schema.ToOrderResponse(f.order), nil
+}
+
+func (f *fakeOrderController) GetOrderByID(_ context.Context, id uint64) (model.Order, error) {
+	if f.err != nil {
+		return model.Order{}, f.err
+	}
+	if id != f.order.ID {
+		return model.Order{}, errs.NotFoundError{Message: "Order not found"}
+	}
+	return f.order, nil
+}
+
+func newOrderTestRouter(oc *fakeOrderController) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	NewOrderRoutes(engine.Group("/v1"), logger.New("test"), oc)
+	return engine
+}
+
+// orderTestResponse decodes both successful and error responses.
+type orderTestResponse struct {
+	Data  schema.OrderResponse `json:"data"`
+	Code  int                  `json:"code"`
+	Error string               `json:"error"`
+}
+
+func TestOrderRoutes(t *testing.T) {
+	order := model.Order{
+		ID:          7,
+		Cost:        money.NewFromInt(100000000),
+		TotalAmount: money.NewFromInt(90000000),
+		Currency:    "VND",
+		Redemptions: []model.CouponRedemption{{CouponCode: "SALE10", DiscountAmount: money.NewFromInt(10000000)}},
+	}
+	validBody := `{"cost": 100000000, "created_at": "2026-10-17T10:00:00Z", "coupon_code": "SALE10"}`
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		err        error
+		wantStatus int
+		wantCode   int
+		wantTotal  money.Amount
+		wantCalled bool
+	}{
+		{
+			name:       "TC16.1 create order",
+			method:     http.MethodPost,
+			path:       "/v1/orders",
+			body:       validBody,
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusOK,
+			wantTotal:  money.NewFromInt(90000000),
+			wantCalled: true,
+		},
+		{
+			name:       "TC16.2 create order without created_at",
+			method:     http.MethodPost,
+			path:       "/v1/orders",
+			body:       `{"cost": 100000000}`,
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusBadRequest,
+		},
+		{
+			name:       "TC16.3 create order with a rejected coupon",
+			method:     http.MethodPost,
+			path:       "/v1/orders",
+			body:       validBody,
+			err:        errs.BadRequestError{Message: "coupon SALE10 has reached its redemption limit"},
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusBadRequest,
+			wantCalled: true,
+		},
+		{
+			name:       "TC16.4 create order failing to persist",
+			method:     http.MethodPost,
+			path:       "/v1/orders",
+			body:       validBody,
+			err:        context.DeadlineExceeded,
+			wantStatus: http.StatusInternalServerError,
+			wantCalled: true,
+		},
+		{
+			name:       "TC16.5 get order",
+			method:     http.MethodGet,
+			path:       "/v1/orders/7",
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusOK,
+			wantTotal:  money.NewFromInt(90000000),
+		},
+		{
+			name:       "TC16.6 get missing order",
+			method:     http.MethodGet,
+			path:       "/v1/orders/8",
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusNotFound,
+		},
+		{
+			name:       "TC16.7 get order with an invalid ID",
+			method:     http.MethodGet,
+			path:       "/v1/orders/abc",
+			wantStatus: http.StatusOK,
+			wantCode:   http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oc := &fakeOrderController{order: order, err: tt.err}
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			newOrderTestRouter(oc).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if (oc.req != nil) != tt.wantCalled {
+				t.Errorf("controller called = %v, want %v", oc.req != nil, tt.wantCalled)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var resp orderTestResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if resp.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d: %s", resp.Code, tt.wantCode, resp.Error)
+			}
+			if tt.wantCode == http.StatusOK && !resp.Data.TotalAmount.Equal(tt.wantTotal) {
+				t.Errorf("total_amount = %v, want %v", resp.Data.TotalAmount, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -12,6 +12,11 @@ func NewRouter(handler *gin.RouterGroup,
 	l logger.Interface,
 	couponController controller.CouponController,
 	orderController controller.OrderController,
+	customerController controller.CustomerController,
+	couponBatchController controller.CouponBatchController,
+	campaignController controller.CampaignController,
+	walletController controller.WalletController,
+	flashClaimController controller.FlashClaimController,
 ) {
 	// Routers
 	h := handler.Group("/v1")
@@ -19,6 +24,11 @@ func NewRouter(handler *gin.RouterGroup,
 		NewDefaultRoutes(h, l)
 		NewCouponRoutes(h, l, couponController)
 		NewOrderRoutes(h, l, orderController)
+		NewCustomerRoutes(h, l, customerController)
+		NewCouponBatchRoutes(h, l, couponBatchController)
+		NewCampaignRoutes(h, l, campaignController)
+		NewWalletRoutes(h, l, walletController)
+		NewFlashClaimRoutes(h, l, flashClaimController)
 	}
 
 }
@@ -0,0 +1,86 @@
+package router
+
+import (
+	"coupon-be/internal/controller"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/utils/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WalletRoutes struct {
+	l                logger.Interface
+	walletController controller.WalletController
+}
+
+func NewWalletRoutes(handler *gin.RouterGroup, l logger.Interface, walletController controller.WalletController) {
+	r := &WalletRoutes{l, walletController}
+	h := handler.Group("/customers")
+	{
+		h.GET("/:id/wallet", r.GetWallet)
+		h.POST("/:id/wallet", r.ClaimCoupon)
+	}
+}
+
+// GetWallet godoc
+// @Summary     Get the wallet of a customer
+// @Description List the coupons a customer claimed with their remaining uses. Claims past their expiry are listed as expired
+// @ID          getWallet
+// @Tags        Wallets
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Customer ID"
+// @Param       status query string false "Only list claims in this status" Enums(available, used, expired)
+// @Success     200 {object} schema.Response[schema.WalletResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/customers/{id}/wallet [get]
+func (r *WalletRoutes) GetWallet(c *gin.Context) {
+	wallet, err := r.walletController.GetWallet(c.Request.Context(), c.Param("id"), c.Query("status"))
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.WalletResponse]{
+		Data:    wallet,
+		Message: "Wallet retrieved successfully",
+		Code:    200,
+	})
+}
+
+// ClaimCoupon godoc
+// @Summary     Claim a coupon into a customer's wallet
+// @Description Claim a coupon for a customer. Coupons that require claiming can only be redeemed by customers holding an unexpired claim
+// @ID          claimCoupon
+// @Tags        Wallets
+// @Accept      json
+// @Produce     json
+// @Param       id path string true "Customer ID"
+// @Param       claim body schema.ClaimCouponRequest true "Coupon to claim"
+// @Success     200 {object} schema.Response[schema.WalletCouponResponse]
+// @Failure     400 {object} schema.ErrorResponse
+// @Failure     404 {object} schema.ErrorResponse
+// @Failure     500 {object} schema.ErrorResponse
+// @Router      /v1/customers/{id}/wallet [post]
+func (r *WalletRoutes) ClaimCoupon(c *gin.Context) {
+	var req schema.ClaimCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		r.l.Error("Failed to bind JSON for ClaimCoupon", "error", err)
+		schema.NewErrorResponse(c, errs.BadRequestError{Message: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	coupon, err := r.walletController.ClaimCoupon(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		schema.NewErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(200, schema.Response[schema.WalletCouponResponse]{
+		Data:    coupon,
+		Message: "Coupon claimed successfully",
+		Code:    200,
+	})
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
+	"time"
+)
+
+type CreateCampaignRequest struct {
+	Name        *string         `json:"name" binding:"required,max=255"`
+	Description *string         `json:"description"`
+	Owner       *string         `json:"owner" binding:"required,max=255"`
+	Budget      *money.Amount   `json:"budget" binding:"omitempty,gt=0"`
+	Currency    *money.Currency `json:"currency" binding:"omitempty,iso4217"`
+	StartsAt    *time.Time      `json:"starts_at" binding:"required"`
+	EndsAt      *time.Time      `json:"ends_at" binding:"required"`
+}
+
+// UpdateCampaignRequest changes the fields it sets and leaves the others as
+// they are.
+type UpdateCampaignRequest struct {
+	Name        *string       `json:"name" binding:"omitempty,min=1,max=255"`
+	Description *string       `json:"description"`
+	Owner       *string       `json:"owner" binding:"omitempty,min=1,max=255"`
+	Budget      *money.Amount `json:"budget" binding:"omitempty,gt=0"`
+	StartsAt    *time.Time    `json:"starts_at"`
+	EndsAt      *time.Time    `json:"ends_at"`
+}
+
+type CampaignResponse struct {
+	ID          uint64         `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Owner       string         `json:"owner"`
+	Budget      *money.Amount  `json:"budget"`
+	Currency    money.Currency `json:"currency"`
+	StartsAt    time.Time      `json:"starts_at"`
+	EndsAt      time.Time      `json:"ends_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func ToCampaignResponse(c model.Campaign) CampaignResponse {
+	return CampaignResponse{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Owner:       c.Owner,
+		Budget:      c.Budget,
+		Currency:    c.Currency.OrDefault(),
+		StartsAt:    c.StartsAt,
+		EndsAt:      c.EndsAt,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+func ToCampaignResponses(campaigns []model.Campaign) []CampaignResponse {
+	responses := make([]CampaignResponse, len(campaigns))
+	for i, c := range campaigns {
+		responses[i] = ToCampaignResponse(c)
+	}
+	return responses
+}
+
+// CampaignStatsResponse aggregates the redemptions of a campaign's coupons.
+// BudgetRemaining is omitted for campaigns without a budget.
+type CampaignStatsResponse struct {
+	CampaignID      uint64         `json:"campaign_id"`
+	Coupons         int64          `json:"coupons"`
+	Redemptions     int64          `json:"redemptions"`
+	Orders          int64          `json:"orders"`
+	Customers       int64          `json:"customers"`
+	DiscountAmount  money.Amount   `json:"discount_amount"`
+	Currency        money.Currency `json:"currency"`
+	Budget          *money.Amount  `json:"budget"`
+	BudgetRemaining *money.Amount  `json:"budget_remaining,omitempty"`
+}
+
+func ToCampaignStatsResponse(c model.Campaign, s model.CampaignStats) CampaignStatsResponse {
+	response := CampaignStatsResponse{
+		CampaignID:     c.ID,
+		Coupons:        s.Coupons,
+		Redemptions:    s.Redemptions,
+		Orders:         s.Orders,
+		Customers:      s.Customers,
+		DiscountAmount: s.DiscountAmount,
+		Currency:       c.Currency.OrDefault(),
+		Budget:         c.Budget,
+	}
+	if c.Budget != nil {
+		remaining := money.Max(c.Budget.Sub(s.DiscountAmount), money.Zero)
+		response.BudgetRemaining = &remaining
+	}
+	return response
+}
@@ -2,51 +2,168 @@ package schema
 
 import (
 	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
 	"time"
 )
 
 type CreateCouponRequest struct {
-	CouponCode  *string            `json:"coupon_code" binding:"required"`
-	Title       *string            `json:"title" binding:"required"`
-	Description *string            `json:"description" binding:"required"`
-	CouponType  *model.CouponType  `json:"coupon_type" binding:"required,oneof=fixed percentage"`
-	Usage       *model.CouponUsage `json:"usage" binding:"required,oneof=manual auto"`
-	ExpiredAt   *time.Time         `json:"expired_at" binding:"required"`
-	CouponValue *float64           `json:"coupon_value" binding:"required,gt=0"`
+	CouponCode                *string                `json:"coupon_code" binding:"required"`
+	Title                     *string                `json:"title" binding:"required"`
+	Description               *string                `json:"description" binding:"required"`
+	CouponType                *model.CouponType      `json:"coupon_type" binding:"required"`
+	Usage                     *model.CouponUsage     `json:"usage" binding:"required,oneof=manual auto"`
+	Status                    *model.CouponStatus    `json:"status" binding:"omitempty,oneof=draft active"`
+	StackingGroup             *string                `json:"stacking_group"`
+	Exclusive                 *bool                  `json:"exclusive"`
+	Priority                  *int                   `json:"priority"`
+	StartsAt                  *time.Time             `json:"starts_at"`
+	ExpiredAt                 *time.Time             `json:"expired_at" binding:"required"`
+	ValidityWindows           *model.CouponWindows   `json:"validity_windows"`
+	TimeZone                  *string                `json:"time_zone" binding:"omitempty,timezone"`
+	CouponValue               *money.Amount          `json:"coupon_value" binding:"omitempty,gt=0"`
+	Currency                  *money.Currency        `json:"currency" binding:"omitempty,iso4217"`
+	Tiers                     *model.CouponTiers     `json:"tiers"`
+	Targeting                 *model.CouponTargeting `json:"targeting"`
+	BuyXGetY                  *model.CouponBuyXGetY  `json:"buy_x_get_y"`
+	AllowedCustomerIDs        *model.StringList      `json:"allowed_customer_ids" binding:"omitempty,dive,required"`
+	CustomerSegment           *string                `json:"customer_segment" binding:"omitempty,min=1"`
+	FirstOrderOnly            *bool                  `json:"first_order_only"`
+	Channels                  *model.StringList      `json:"channels" binding:"omitempty,dive,required,max=64"`
+	PaymentMethods            *model.StringList      `json:"payment_methods" binding:"omitempty,dive,required,max=64"`
+	CampaignID                *uint64                `json:"campaign_id" binding:"omitempty,gt=0"`
+	MaxRedemptions            *int                   `json:"max_redemptions" binding:"omitempty,gt=0"`
+	MaxRedemptionsPerCustomer *int                   `json:"max_redemptions_per_customer" binding:"omitempty,gt=0"`
+	MinOrderAmount            *money.Amount          `json:"min_order_amount" binding:"omitempty,gte=0"`
+	MaxDiscountAmount         *money.Amount          `json:"max_discount_amount" binding:"omitempty,gt=0"`
+	Budget                    *money.Amount          `json:"budget" binding:"omitempty,gt=0"`
+	AllowPartialDiscount      *bool                  `json:"allow_partial_discount"`
+	RequiresClaim             *bool                  `json:"requires_claim"`
+	ClaimValidityDays         *int                   `json:"claim_validity_days" binding:"omitempty,gt=0"`
+	ClaimLimit                *int                   `json:"claim_limit" binding:"omitempty,gt=0"`
 }
 
 type UpdateCouponRequest struct {
-	Title       *string            `json:"title"`
-	Description *string            `json:"description"`
-	CouponType  *model.CouponType  `json:"coupon_type" binding:"omitempty,oneof=fixed percentage"`
-	Usage       *model.CouponUsage `json:"usage"`
-	ExpiredAt   *time.Time         `json:"expired_at"`
-	CouponValue *float64           `json:"coupon_value"`
+	Title                     *string                `json:"title"`
+	Description               *string                `json:"description"`
+	CouponType                *model.CouponType      `json:"coupon_type"`
+	Usage                     *model.CouponUsage     `json:"usage"`
+	StackingGroup             *string                `json:"stacking_group"`
+	Exclusive                 *bool                  `json:"exclusive"`
+	Priority                  *int                   `json:"priority"`
+	StartsAt                  *time.Time             `json:"starts_at"`
+	ExpiredAt                 *time.Time             `json:"expired_at"`
+	ValidityWindows           *model.CouponWindows   `json:"validity_windows"`
+	TimeZone                  *string                `json:"time_zone" binding:"omitempty,timezone"`
+	CouponValue               *money.Amount          `json:"coupon_value" binding:"omitempty,gt=0"`
+	Currency                  *money.Currency        `json:"currency" binding:"omitempty,iso4217"`
+	Tiers                     *model.CouponTiers     `json:"tiers"`
+	Targeting                 *model.CouponTargeting `json:"targeting"`
+	BuyXGetY                  *model.CouponBuyXGetY  `json:"buy_x_get_y"`
+	AllowedCustomerIDs        *model.StringList      `json:"allowed_customer_ids" binding:"omitempty,dive,required"`
+	CustomerSegment           *string                `json:"customer_segment" binding:"omitempty,min=1"`
+	FirstOrderOnly            *bool                  `json:"first_order_only"`
+	Channels                  *model.StringList      `json:"channels" binding:"omitempty,dive,required,max=64"`
+	PaymentMethods            *model.StringList      `json:"payment_methods" binding:"omitempty,dive,required,max=64"`
+	CampaignID                *uint64                `json:"campaign_id" binding:"omitempty,gt=0"`
+	MaxRedemptions            *int                   `json:"max_redemptions" binding:"omitempty,gt=0"`
+	MaxRedemptionsPerCustomer *int                   `json:"max_redemptions_per_customer" binding:"omitempty,gt=0"`
+	MinOrderAmount            *money.Amount          `json:"min_order_amount" binding:"omitempty,gte=0"`
+	MaxDiscountAmount         *money.Amount          `json:"max_discount_amount" binding:"omitempty,gt=0"`
+	Budget                    *money.Amount          `json:"budget" binding:"omitempty,gt=0"`
+	AllowPartialDiscount      *bool                  `json:"allow_partial_discount"`
+	RequiresClaim             *bool                  `json:"requires_claim"`
+	ClaimValidityDays         *int                   `json:"claim_validity_days" binding:"omitempty,gt=0"`
+	ClaimLimit                *int                   `json:"claim_limit" binding:"omitempty,gt=0"`
+	// Clear lists the nullable fields to set to null. Fields left out of the
+	// request keep their value.
+	Clear []string `json:"clear" column:"-" binding:"omitempty,unique,dive,oneof=stacking_group starts_at validity_windows time_zone tiers targeting buy_x_get_y allowed_customer_ids customer_segment channels payment_methods campaign_id max_redemptions max_redemptions_per_customer min_order_amount max_discount_amount budget claim_validity_days claim_limit"`
 }
 
 type CouponResponse struct {
-	CouponCode  string            `json:"coupon_code"`
-	Title       string            `json:"title"`
-	Description string            `json:"description"`
-	CouponType  model.CouponType  `json:"coupon_type"`
-	Usage       model.CouponUsage `json:"usage"`
-	ExpiredAt   time.Time         `json:"expired_at"`
-	CouponValue float64           `json:"coupon_value"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	CouponCode                string                 `json:"coupon_code"`
+	Title                     string                 `json:"title"`
+	Description               string                 `json:"description"`
+	CouponType                model.CouponType       `json:"coupon_type"`
+	Usage                     model.CouponUsage      `json:"usage"`
+	Status                    model.CouponStatus     `json:"status"`
+	StackingGroup             *string                `json:"stacking_group"`
+	Exclusive                 bool                   `json:"exclusive"`
+	Priority                  int                    `json:"priority"`
+	StartsAt                  *time.Time             `json:"starts_at"`
+	ExpiredAt                 time.Time              `json:"expired_at"`
+	ValidityWindows           model.CouponWindows    `json:"validity_windows"`
+	TimeZone                  *string                `json:"time_zone"`
+	CouponValue               money.Amount           `json:"coupon_value"`
+	Currency                  money.Currency         `json:"currency"`
+	Tiers                     model.CouponTiers      `json:"tiers"`
+	Targeting                 *model.CouponTargeting `json:"targeting"`
+	BuyXGetY                  *model.CouponBuyXGetY  `json:"buy_x_get_y"`
+	AllowedCustomerIDs        model.StringList       `json:"allowed_customer_ids"`
+	CustomerSegment           *string                `json:"customer_segment"`
+	FirstOrderOnly            bool                   `json:"first_order_only"`
+	Channels                  model.StringList       `json:"channels"`
+	PaymentMethods            model.StringList       `json:"payment_methods"`
+	ParentCouponCode          *string                `json:"parent_coupon_code"`
+	BatchID                   *uint64                `json:"batch_id"`
+	CampaignID                *uint64                `json:"campaign_id"`
+	MaxRedemptions            *int                   `json:"max_redemptions"`
+	MaxRedemptionsPerCustomer *int                   `json:"max_redemptions_per_customer"`
+	MinOrderAmount            *money.Amount          `json:"min_order_amount"`
+	MaxDiscountAmount         *money.Amount          `json:"max_discount_amount"`
+	Budget                    *money.Amount          `json:"budget"`
+	AllowPartialDiscount      bool                   `json:"allow_partial_discount"`
+	RequiresClaim             bool                   `json:"requires_claim"`
+	ClaimValidityDays         *int                   `json:"claim_validity_days"`
+	ClaimLimit                *int                   `json:"claim_limit"`
+	CreatedAt                 time.Time              `json:"created_at"`
+	UpdatedAt                 time.Time              `json:"updated_at"`
+	DeletedAt                 *time.Time             `json:"deleted_at,omitempty"`
 }
 
 func ToCouponResponse(c model.Coupon) CouponResponse {
+	var deletedAt *time.Time
+	if c.DeletedAt.Valid {
+		deletedAt = &c.DeletedAt.Time
+	}
 	return CouponResponse{
-		CouponCode:  c.CouponCode,
-		Title:       c.Title,
-		Description: c.Description,
-		CouponType:  c.CouponType,
-		Usage:       c.Usage,
-		ExpiredAt:   c.ExpiredAt,
-		CouponValue: c.CouponValue,
-		CreatedAt:   c.CreatedAt,
-		UpdatedAt:   c.UpdatedAt,
+		CouponCode:                c.CouponCode,
+		Title:                     c.Title,
+		Description:               c.Description,
+		CouponType:                c.CouponType,
+		Usage:                     c.Usage,
+		Status:                    c.Status,
+		StackingGroup:             c.StackingGroup,
+		Exclusive:                 c.Exclusive,
+		Priority:                  c.Priority,
+		StartsAt:                  c.StartsAt,
+		ExpiredAt:                 c.ExpiredAt,
+		ValidityWindows:           c.ValidityWindows,
+		TimeZone:                  c.TimeZone,
+		CouponValue:               c.CouponValue,
+		Currency:                  c.Currency.OrDefault(),
+		Tiers:                     c.Tiers,
+		Targeting:                 c.Targeting,
+		BuyXGetY:                  c.BuyXGetY,
+		AllowedCustomerIDs:        c.AllowedCustomerIDs,
+		CustomerSegment:           c.CustomerSegment,
+		FirstOrderOnly:            c.FirstOrderOnly,
+		Channels:                  c.Channels,
+		PaymentMethods:            c.PaymentMethods,
+		ParentCouponCode:          c.ParentCouponCode,
+		BatchID:                   c.BatchID,
+		CampaignID:                c.CampaignID,
+		MaxRedemptions:            c.MaxRedemptions,
+		MaxRedemptionsPerCustomer: c.MaxRedemptionsPerCustomer,
+		MinOrderAmount:            c.MinOrderAmount,
+		MaxDiscountAmount:         c.MaxDiscountAmount,
+		Budget:                    c.Budget,
+		AllowPartialDiscount:      c.AllowPartialDiscount,
+		RequiresClaim:             c.RequiresClaim,
+		ClaimValidityDays:         c.ClaimValidityDays,
+		ClaimLimit:                c.ClaimLimit,
+		CreatedAt:                 c.CreatedAt,
+		UpdatedAt:                 c.UpdatedAt,
+		DeletedAt:                 deletedAt,
 	}
 }
 
@@ -57,3 +174,33 @@ func ToCouponResponses(coupons []model.Coupon) []CouponResponse {
 	}
 	return responses
 }
+
+type CouponRecommendationResponse struct {
+	Rank           int                 `json:"rank"`
+	DiscountAmount money.Amount        `json:"discount_amount"`
+	TotalAmount    money.Amount        `json:"total_amount"`
+	Currency       money.Currency      `json:"currency"`
+	Tier           *CouponTierResponse `json:"tier,omitempty"`
+	Coupon         CouponResponse      `json:"coupon"`
+}
+
+// CouponTierResponse is the tier of a tiered coupon an order reached.
+type CouponTierResponse struct {
+	Level     int          `json:"level"`
+	Threshold money.Amount `json:"threshold"`
+	Discount  money.Amount `json:"discount"`
+}
+
+// ToCouponTierResponse returns the tier at the 1-based level, or nil if the
+// coupon has no such tier.
+func ToCouponTierResponse(c model.Coupon, level int) *CouponTierResponse {
+	if level < 1 || level > len(c.Tiers) {
+		return nil
+	}
+	tier := c.Tiers[level-1]
+	return &CouponTierResponse{
+		Level:     level,
+		Threshold: tier.Threshold,
+		Discount:  tier.Discount,
+	}
+}
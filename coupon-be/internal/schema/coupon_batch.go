@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"coupon-be/internal/model"
+	"time"
+)
+
+// CreateCouponBatchRequest describes the codes to generate. Alphabet defaults
+// to letters and digits that are hard to misread and Length to 8.
+type CreateCouponBatchRequest struct {
+	Quantity   int     `json:"quantity" binding:"required,gt=0,lte=100000"`
+	Prefix     *string `json:"prefix" binding:"omitempty,max=32,alphanum"`
+	Alphabet   *string `json:"alphabet" binding:"omitempty,min=2,max=128,printascii,excludesall= "`
+	Length     *int    `json:"length" binding:"omitempty,gt=0,lte=64"`
+	CheckDigit *bool   `json:"check_digit"`
+}
+
+type CouponBatchResponse struct {
+	ID               uint64                  `json:"id"`
+	ParentCouponCode string                  `json:"parent_coupon_code"`
+	Quantity         int                     `json:"quantity"`
+	Generated        int                     `json:"generated"`
+	Prefix           string                  `json:"prefix"`
+	Alphabet         string                  `json:"alphabet"`
+	Length           int                     `json:"length"`
+	CheckDigit       bool                    `json:"check_digit"`
+	Status           model.CouponBatchStatus `json:"status"`
+	Error            *string                 `json:"error"`
+	CompletedAt      *time.Time              `json:"completed_at"`
+	CreatedAt        time.Time               `json:"created_at"`
+}
+
+func ToCouponBatchResponse(b model.CouponBatch) CouponBatchResponse {
+	return CouponBatchResponse{
+		ID:               b.ID,
+		ParentCouponCode: b.ParentCouponCode,
+		Quantity:         b.Quantity,
+		Generated:        b.Generated,
+		Prefix:           b.Prefix,
+		Alphabet:         b.Alphabet,
+		Length:           b.Length,
+		CheckDigit:       b.CheckDigit,
+		Status:           b.Status,
+		Error:            b.Error,
+		CompletedAt:      b.CompletedAt,
+		CreatedAt:        b.CreatedAt,
+	}
+}
@@ -0,0 +1,10 @@
+package schema
+
+type UpdateCustomerSegmentsRequest struct {
+	Segments []string `json:"segments" binding:"required,dive,required,max=255"`
+}
+
+type CustomerSegmentsResponse struct {
+	CustomerID string   `json:"customer_id"`
+	Segments   []string `json:"segments"`
+}
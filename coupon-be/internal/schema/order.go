@@ -1,17 +1,162 @@
 package schema
 
-import "time"
+import (
+	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
+	"time"
+)
 
 type CreateMockOrderRequest struct {
-	Cost       float64   `json:"cost" binding:"required"`
-	CreatedAt  time.Time `json:"created_at" binding:"required"`
-	CouponCode *string   `json:"coupon_code"`
+	Cost          money.Amount       `json:"cost" binding:"required_without=Items"`
+	Items         []OrderItemRequest `json:"items" binding:"omitempty,dive"`
+	ShippingFee   money.Amount       `json:"shipping_fee" binding:"omitempty,gte=0"`
+	Currency      money.Currency     `json:"currency" binding:"omitempty,iso4217"`
+	CreatedAt     time.Time          `json:"created_at" binding:"required"`
+	CouponCode    *string            `json:"coupon_code"`
+	CouponCodes   []string           `json:"coupon_codes" binding:"omitempty,dive,required"`
+	CustomerID    *string            `json:"customer_id"`
+	Channel       *string            `json:"channel" binding:"omitempty,min=1,max=64"`
+	PaymentMethod *string            `json:"payment_method" binding:"omitempty,min=1,max=64"`
 }
 
+// OrderItemRequest is one line of an order.
+type OrderItemRequest struct {
+	SKU       string       `json:"sku" binding:"required"`
+	Category  string       `json:"category"`
+	Quantity  int          `json:"quantity" binding:"required,gt=0"`
+	UnitPrice money.Amount `json:"unit_price" binding:"gte=0"`
+}
+
+// Amount returns the price of the line before discounts.
+func (i OrderItemRequest) Amount() money.Amount {
+	return i.UnitPrice.Mul(money.NewFromInt(int64(i.Quantity)))
+}
+
+// ItemsTotal returns the sum of the order lines.
+func (r CreateMockOrderRequest) ItemsTotal() money.Amount {
+	total := money.Zero
+	for _, item := range r.Items {
+		total = total.Add(item.Amount())
+	}
+	return total
+}
+
+// OrderCurrency returns the currency of the order, falling back to the
+// default currency when the request does not name one.
+func (r CreateMockOrderRequest) OrderCurrency() money.Currency {
+	return r.Currency.OrDefault()
+}
+
+// OrderTotal returns what the order costs before coupons: the goods plus
+// shipping.
+func (r CreateMockOrderRequest) OrderTotal() money.Amount {
+	return r.Cost.Add(r.ShippingFee)
+}
+
+// CreateMockOrderResponse prices an order. DiscountAmount is taken off the
+// subtotal and ShippingDiscountAmount off the shipping fee.
 type CreateMockOrderResponse struct {
-	Cost        float64         `json:"cost"`
-	CreatedAt   time.Time       `json:"created_at"`
-	CouponCode  *string         `json:"coupon_code,omitempty"`
-	TotalAmount float64         `json:"total_amount"`
-	Coupon      *CouponResponse `json:"coupon"`
+	Cost                   money.Amount            `json:"cost"`
+	Subtotal               money.Amount            `json:"subtotal"`
+	ShippingFee            money.Amount            `json:"shipping_fee"`
+	DiscountAmount         money.Amount            `json:"discount_amount"`
+	ShippingDiscountAmount money.Amount            `json:"shipping_discount_amount"`
+	Currency               money.Currency          `json:"currency"`
+	CreatedAt              time.Time               `json:"created_at"`
+	CouponCode             *string                 `json:"coupon_code,omitempty"`
+	TotalAmount            money.Amount            `json:"total_amount"`
+	Items                  []OrderItemResponse     `json:"items,omitempty"`
+	Coupon                 *CouponResponse         `json:"coupon"`
+	Coupons                []AppliedCouponResponse `json:"coupons"`
+	AutoApplied            bool                    `json:"auto_applied"`
+	Reason                 string                  `json:"reason,omitempty"`
+}
+
+// AppliedCouponResponse is one step of a coupon stack: the discount the
+// coupon took off and the total left after it.
+type AppliedCouponResponse struct {
+	CouponCode     string              `json:"coupon_code"`
+	DiscountAmount money.Amount        `json:"discount_amount"`
+	TotalAmount    money.Amount        `json:"total_amount"`
+	Tier           *CouponTierResponse `json:"tier,omitempty"`
+	Coupon         CouponResponse      `json:"coupon"`
+}
+
+// OrderItemResponse is one line of an order with the discount coupons took
+// off it.
+type OrderItemResponse struct {
+	SKU            string       `json:"sku"`
+	Category       string       `json:"category"`
+	Quantity       int          `json:"quantity"`
+	UnitPrice      money.Amount `json:"unit_price"`
+	Amount         money.Amount `json:"amount"`
+	DiscountAmount money.Amount `json:"discount_amount"`
+	TotalAmount    money.Amount `json:"total_amount"`
+}
+
+func ToOrderItemResponses(items []model.OrderItem) []OrderItemResponse {
+	responses := make([]OrderItemResponse, len(items))
+	for i, item := range items {
+		responses[i] = OrderItemResponse{
+			SKU:            item.SKU,
+			Category:       item.Category,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.UnitPrice,
+			Amount:         item.TotalAmount.Add(item.DiscountAmount),
+			DiscountAmount: item.DiscountAmount,
+			TotalAmount:    item.TotalAmount,
+		}
+	}
+	return responses
+}
+
+type CouponRedemptionResponse struct {
+	CouponCode     string       `json:"coupon_code"`
+	DiscountAmount money.Amount `json:"discount_amount"`
+	AutoApplied    bool         `json:"auto_applied"`
+}
+
+type OrderResponse struct {
+	ID                     uint64                     `json:"id"`
+	CustomerID             *string                    `json:"customer_id"`
+	Channel                *string                    `json:"channel"`
+	PaymentMethod          *string                    `json:"payment_method"`
+	Cost                   money.Amount               `json:"cost"`
+	Subtotal               money.Amount               `json:"subtotal"`
+	ShippingFee            money.Amount               `json:"shipping_fee"`
+	DiscountAmount         money.Amount               `json:"discount_amount"`
+	ShippingDiscountAmount money.Amount               `json:"shipping_discount_amount"`
+	TotalAmount            money.Amount               `json:"total_amount"`
+	Currency               money.Currency             `json:"currency"`
+	Items                  []OrderItemResponse        `json:"items,omitempty"`
+	Redemptions            []CouponRedemptionResponse `json:"redemptions"`
+	Reason                 string                     `json:"reason,omitempty"`
+	CreatedAt              time.Time                  `json:"created_at"`
+}
+
+func ToOrderResponse(o model.Order) OrderResponse {
+	redemptions := make([]CouponRedemptionResponse, len(o.Redemptions))
+	for i, r := range o.Redemptions {
+		redemptions[i] = CouponRedemptionResponse{
+			CouponCode:     r.CouponCode,
+			DiscountAmount: r.DiscountAmount,
+			AutoApplied:    r.AutoApplied,
+		}
+	}
+	return OrderResponse{
+		ID:                     o.ID,
+		CustomerID:             o.CustomerID,
+		Channel:                o.Channel,
+		PaymentMethod:          o.PaymentMethod,
+		Cost:                   o.Cost,
+		Subtotal:               o.Cost,
+		ShippingFee:            o.ShippingFee,
+		DiscountAmount:         o.DiscountAmount,
+		ShippingDiscountAmount: o.ShippingDiscountAmount,
+		TotalAmount:            o.TotalAmount,
+		Currency:               o.Currency.OrDefault(),
+		Items:                  ToOrderItemResponses(o.Items),
+		Redemptions:            redemptions,
+		CreatedAt:              o.CreatedAt,
+	}
 }
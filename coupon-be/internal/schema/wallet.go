@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"coupon-be/internal/model"
+	"time"
+)
+
+type ClaimCouponRequest struct {
+	CouponCode string `json:"coupon_code" binding:"required"`
+}
+
+// WalletCouponResponse describes a claimed coupon. RemainingUses is null for
+// coupons without a per-customer redemption limit.
+type WalletCouponResponse struct {
+	ID            uint64                   `json:"id"`
+	CustomerID    string                   `json:"customer_id"`
+	CouponCode    string                   `json:"coupon_code"`
+	Status        model.WalletCouponStatus `json:"status"`
+	RemainingUses *int                     `json:"remaining_uses"`
+	ClaimedAt     time.Time                `json:"claimed_at"`
+	ExpiresAt     time.Time                `json:"expires_at"`
+	Coupon        CouponResponse           `json:"coupon"`
+}
+
+// WalletResponse lists the coupons in a customer's wallet. Available counts
+// the claims that can still be redeemed, whatever the status filter.
+type WalletResponse struct {
+	CustomerID string                 `json:"customer_id"`
+	Available  int                    `json:"available"`
+	Coupons    []WalletCouponResponse `json:"coupons"`
+}
+
+// ToWalletCouponResponse describes the claim at the given time after used
+// redemptions of the coupon by the customer.
+func ToWalletCouponResponse(w model.WalletCoupon, used int64, at time.Time) WalletCouponResponse {
+	return WalletCouponResponse{
+		ID:            w.ID,
+		CustomerID:    w.CustomerID,
+		CouponCode:    w.CouponCode,
+		Status:        w.Status(at, used),
+		RemainingUses: w.RemainingUses(used),
+		ClaimedAt:     w.ClaimedAt,
+		ExpiresAt:     w.ExpiresAt,
+		Coupon:        ToCouponResponse(w.Coupon),
+	}
+}
+
+type FlashClaimRequest struct {
+	CustomerID string `json:"customer_id" binding:"required,max=255"`
+}
+
+// FlashClaimResponse describes a flash sale claim. The claim is taken from
+// the stock right away and shows up in the customer's wallet once persisted.
+type FlashClaimResponse struct {
+	CouponCode string    `json:"coupon_code"`
+	CustomerID string    `json:"customer_id"`
+	Remaining  int64     `json:"remaining"`
+	ClaimedAt  time.Time `json:"claimed_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// FlashSaleResponse describes the stock of a flash sale. Pending claims are
+// taken from the stock but not persisted yet.
+type FlashSaleResponse struct {
+	CouponCode string `json:"coupon_code"`
+	ClaimLimit int    `json:"claim_limit"`
+	Remaining  int64  `json:"remaining"`
+	Claimed    int64  `json:"claimed"`
+	Pending    int64  `json:"pending"`
+}
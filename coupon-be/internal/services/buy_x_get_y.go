@@ -0,0 +1,150 @@
+package services
+
+import (
+	"coupon-be/internal/model"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/money"
+	"fmt"
+	"sort"
+)
+
+// buyXGetYDiscount takes the reward discount off the cheapest reward items,
+// once for every complete set of trigger and reward items in the order.
+type buyXGetYDiscount struct{}
+
+func (buyXGetYDiscount) Validate(coupon model.Coupon) error {
+	rules := coupon.BuyXGetY
+	if rules == nil {
+		return fmt.Errorf("buy_x_get_y is required for %s coupons", coupon.CouponType)
+	}
+	if rules.Buy.Quantity <= 0 || rules.Get.Quantity <= 0 {
+		return fmt.Errorf("buy and get quantities must be greater than 0")
+	}
+	if len(rules.Buy.SKUs) == 0 && len(rules.Buy.Categories) == 0 {
+		return fmt.Errorf("buy must name at least one sku or category")
+	}
+	if len(rules.Get.SKUs) == 0 && len(rules.Get.Categories) == 0 {
+		return fmt.Errorf("get must name at least one sku or category")
+	}
+	if discount := rules.RewardDiscount(); !discount.GreaterThan(money.Zero) || discount.GreaterThan(money.NewFromInt(100)) {
+		return fmt.Errorf("discount must be between 0 and 100")
+	}
+	if rules.MaxSets != nil && *rules.MaxSets <= 0 {
+		return fmt.Errorf("max_sets must be greater than 0")
+	}
+	return nil
+}
+
+func (buyXGetYDiscount) CheckOrder(coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if coupon.BuyXGetY == nil {
+		return nil
+	}
+	if len(req.Items) == 0 {
+		return fmt.Errorf("coupon %s only applies to order items and the order has no items", coupon.CouponCode)
+	}
+	lines := orderAmounts(req).targeted(coupon).Lines
+	if buyXGetYSets(*coupon.BuyXGetY, lines) == 0 {
+		return fmt.Errorf("order does not contain %d trigger items and %d reward items for coupon %s", coupon.BuyXGetY.Buy.Quantity, coupon.BuyXGetY.Get.Quantity, coupon.CouponCode)
+	}
+	return nil
+}
+
+func (buyXGetYDiscount) Apply(coupon model.Coupon, amounts OrderAmounts) (OrderAmounts, error) {
+	if coupon.BuyXGetY == nil {
+		return amounts, nil
+	}
+	rules := *coupon.BuyXGetY
+	sets := buyXGetYSets(rules, amounts.Lines)
+	if sets == 0 {
+		return amounts, nil
+	}
+
+	// Items matching both rules may be used as trigger or reward items; keep
+	// enough of them back to complete the trigger side of every set.
+	triggerOnly, _, both := countBuyXGetYItems(rules, amounts.Lines)
+	sharedRewards := both - max(0, sets*rules.Buy.Quantity-triggerOnly)
+	rewards := sets * rules.Get.Quantity
+
+	lines := make([]LineAmount, len(amounts.Lines))
+	copy(lines, amounts.Lines)
+	candidates := make([]int, 0, len(lines))
+	for i, line := range lines {
+		if rules.Get.Matches(line.Item.SKU, line.Item.Category) && line.Item.Quantity > 0 {
+			candidates = append(candidates, i)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return unitAmount(lines[candidates[i]]).LessThan(unitAmount(lines[candidates[j]]))
+	})
+
+	total := money.Zero
+	for _, i := range candidates {
+		if rewards == 0 {
+			break
+		}
+		line := lines[i]
+		units := min(line.Item.Quantity, rewards)
+		shared := rules.Buy.Matches(line.Item.SKU, line.Item.Category)
+		if shared {
+			units = min(units, sharedRewards)
+			sharedRewards -= units
+		}
+		if units == 0 {
+			continue
+		}
+		rewards -= units
+
+		discount := line.Amount.Mul(money.NewFromInt(int64(units))).Div(money.NewFromInt(int64(line.Item.Quantity))).
+			Percent(rules.RewardDiscount()).RoundFor(coupon.Currency)
+		if coupon.MaxDiscountAmount != nil {
+			discount = money.Min(discount, coupon.MaxDiscountAmount.Sub(total))
+		}
+		lines[i].Amount = line.Amount.Sub(discount)
+		total = total.Add(discount)
+	}
+
+	amounts.Lines = lines
+	amounts.Subtotal = amounts.Subtotal.Sub(total)
+	return amounts, nil
+}
+
+// buyXGetYSets returns how many complete sets of trigger and reward items the
+// lines hold. Each item counts towards one set side only.
+func buyXGetYSets(rules model.CouponBuyXGetY, lines []LineAmount) int {
+	if rules.Buy.Quantity <= 0 || rules.Get.Quantity <= 0 {
+		return 0
+	}
+	triggerOnly, rewardOnly, both := countBuyXGetYItems(rules, lines)
+	sets := min(
+		(triggerOnly+both)/rules.Buy.Quantity,
+		(rewardOnly+both)/rules.Get.Quantity,
+		(triggerOnly+rewardOnly+both)/(rules.Buy.Quantity+rules.Get.Quantity),
+	)
+	if rules.MaxSets != nil {
+		sets = min(sets, *rules.MaxSets)
+	}
+	return sets
+}
+
+// countBuyXGetYItems counts the items matching only the trigger rule, only
+// the reward rule and both of them.
+func countBuyXGetYItems(rules model.CouponBuyXGetY, lines []LineAmount) (triggerOnly, rewardOnly, both int) {
+	for _, line := range lines {
+		trigger := rules.Buy.Matches(line.Item.SKU, line.Item.Category)
+		reward := rules.Get.Matches(line.Item.SKU, line.Item.Category)
+		switch {
+		case trigger && reward:
+			both += line.Item.Quantity
+		case trigger:
+			triggerOnly += line.Item.Quantity
+		case reward:
+			rewardOnly += line.Item.Quantity
+		}
+	}
+	return triggerOnly, rewardOnly, both
+}
+
+// unitAmount returns what is left to pay for one item of the line.
+func unitAmount(line LineAmount) money.Amount {
+	return line.Amount.Div(money.NewFromInt(int64(line.Item.Quantity)))
+}
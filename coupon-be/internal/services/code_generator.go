@@ -0,0 +1,96 @@
+package services
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DefaultCodeAlphabet leaves out characters that are easy to misread, such as
+// 0 and O or 1 and I.
+const DefaultCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// CodeTemplate describes generated coupon codes: Prefix followed by Length
+// random characters from Alphabet and, if CheckDigit is set, one Luhn mod N
+// check character that catches single typos and most swapped characters.
+type CodeTemplate struct {
+	Prefix     string
+	Alphabet   string
+	Length     int
+	CheckDigit bool
+}
+
+// Validate rejects templates that cannot produce quantity unique codes. The
+// random part must allow at least ten times as many codes as requested so
+// collisions stay rare.
+func (t CodeTemplate) Validate(quantity int) error {
+	if len(t.Alphabet) < 2 {
+		return fmt.Errorf("alphabet must have at least 2 characters")
+	}
+	seen := make(map[rune]bool, len(t.Alphabet))
+	for _, r := range t.Alphabet {
+		if r > 127 {
+			return fmt.Errorf("alphabet must only contain ASCII characters")
+		}
+		if seen[r] {
+			return fmt.Errorf("alphabet contains %q more than once", r)
+		}
+		seen[r] = true
+	}
+	if t.Length <= 0 {
+		return fmt.Errorf("length must be greater than 0")
+	}
+	if space := math.Pow(float64(len(t.Alphabet)), float64(t.Length)); space < float64(quantity)*10 {
+		return fmt.Errorf("a length of %d over %d characters cannot produce %d unique codes, use a longer length or alphabet", t.Length, len(t.Alphabet), quantity)
+	}
+	return nil
+}
+
+// Generate returns a random code following the template.
+func (t CodeTemplate) Generate() (string, error) {
+	var b strings.Builder
+	b.WriteString(t.Prefix)
+	body := make([]byte, t.Length)
+	max := big.NewInt(int64(len(t.Alphabet)))
+	for i := range body {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		body[i] = t.Alphabet[n.Int64()]
+	}
+	b.Write(body)
+	if t.CheckDigit {
+		b.WriteByte(t.checkCharacter(string(body)))
+	}
+	return b.String(), nil
+}
+
+// HasValidCheckDigit reports whether the code's last character is the check
+// character of its random part.
+func (t CodeTemplate) HasValidCheckDigit(code string) bool {
+	body, ok := strings.CutPrefix(code, t.Prefix)
+	if !ok || len(body) != t.Length+1 {
+		return false
+	}
+	for _, c := range []byte(body) {
+		if strings.IndexByte(t.Alphabet, c) < 0 {
+			return false
+		}
+	}
+	return t.checkCharacter(body[:t.Length]) == body[t.Length]
+}
+
+// checkCharacter computes the Luhn mod N check character of body.
+func (t CodeTemplate) checkCharacter(body string) byte {
+	n := len(t.Alphabet)
+	factor, sum := 2, 0
+	for i := len(body) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(t.Alphabet, body[i])
+		factor = 3 - factor
+		sum += addend/n + addend%n
+	}
+	return t.Alphabet[(n-sum%n)%n]
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateCode(t *testing.T) {
+	template := CodeTemplate{Prefix: "KOL", Alphabet: DefaultCodeAlphabet, Length: 8, CheckDigit: true}
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		code, err := template.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if !strings.HasPrefix(code, "KOL") || len(code) != len("KOL")+8+1 {
+			t.Fatalf("Generate() got = %s, want KOL followed by 9 characters", code)
+		}
+		if !template.HasValidCheckDigit(code) {
+			t.Errorf("HasValidCheckDigit(%s) = false, want true", code)
+		}
+		seen[code] = true
+	}
+	if len(seen) < 1000 {
+		t.Errorf("Generate() produced %d unique codes out of 1000", len(seen))
+	}
+}
+
+func TestCheckDigitCatchesTypos(t *testing.T) {
+	template := CodeTemplate{Alphabet: DefaultCodeAlphabet, Length: 8, CheckDigit: true}
+	code, err := template.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for i := 0; i < template.Length; i++ {
+		for _, c := range []byte(template.Alphabet) {
+			if c == code[i] {
+				continue
+			}
+			typo := code[:i] + string(c) + code[i+1:]
+			if template.HasValidCheckDigit(typo) {
+				t.Errorf("HasValidCheckDigit(%s) = true for a typo of %s", typo, code)
+			}
+		}
+	}
+}
+
+func TestValidateCodeTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template CodeTemplate
+		quantity int
+		wantErr  bool
+	}{
+		{name: "default template", template: CodeTemplate{Alphabet: DefaultCodeAlphabet, Length: 8}, quantity: 50000},
+		{name: "space too small", template: CodeTemplate{Alphabet: "AB", Length: 4}, quantity: 10, wantErr: true},
+		{name: "duplicate characters", template: CodeTemplate{Alphabet: "AAB", Length: 8}, quantity: 10, wantErr: true},
+		{name: "single character alphabet", template: CodeTemplate{Alphabet: "A", Length: 8}, quantity: 1, wantErr: true},
+		{name: "zero length", template: CodeTemplate{Alphabet: DefaultCodeAlphabet}, quantity: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.template.Validate(tt.quantity); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
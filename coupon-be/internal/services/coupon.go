@@ -5,61 +5,678 @@ import (
 	"coupon-be/internal/model"
 	"coupon-be/internal/schema"
 	"coupon-be/pkg/logger"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 )
 
 type CouponService interface {
 	ValidateCoupon(ctx context.Context, coupon model.Coupon, req schema.CreateMockOrderRequest) (bool, error)
-	CalculateAmount(ctx context.Context, coupon *model.Coupon, amount float64) (float64, error)
+	ValidateCouponParams(ctx context.Context, coupon model.Coupon) error
+	CalculateAmount(ctx context.Context, coupon *model.Coupon, amount money.Amount) (money.Amount, error)
+	ValidateStatusTransition(ctx context.Context, coupon model.Coupon, status model.CouponStatus) error
+	RankCoupons(ctx context.Context, coupons []model.Coupon, req schema.CreateMockOrderRequest) []CouponSelection
+	SelectAutoCoupon(ctx context.Context, coupons []model.Coupon, req schema.CreateMockOrderRequest) (*CouponSelection, error)
+	ApplyCoupons(ctx context.Context, coupons []model.Coupon, req schema.CreateMockOrderRequest) ([]AppliedCoupon, OrderAmounts, error)
+	ValidateClaim(ctx context.Context, coupon model.Coupon, customerID string, at time.Time) error
+}
+
+// CouponSelection is a coupon picked for an order together with the total
+// it produces and a human readable reason for picking it.
+type CouponSelection struct {
+	Coupon      model.Coupon
+	Amounts     OrderAmounts
+	TotalAmount money.Amount
+	// Tier is the 1-based level of the tier hit, 0 for coupons without tiers.
+	Tier   int
+	Reason string
+}
+
+// AppliedCoupon is one coupon of a stack applied to an order, with the amount
+// it took off and the total left after it.
+type AppliedCoupon struct {
+	Coupon         model.Coupon
+	DiscountAmount money.Amount
+	TotalAmount    money.Amount
+	// Tier is the 1-based level of the tier hit, 0 for coupons without tiers.
+	Tier int
+}
+
+// couponStatusTransitions lists the statuses a coupon may move to from each
+// status. Archived coupons are final.
+var couponStatusTransitions = map[model.CouponStatus][]model.CouponStatus{
+	model.CouponStatusDraft:  {model.CouponStatusActive, model.CouponStatusArchived},
+	model.CouponStatusActive: {model.CouponStatusPaused, model.CouponStatusArchived},
+	model.CouponStatusPaused: {model.CouponStatusActive, model.CouponStatusArchived},
+}
+
+// RedemptionCounter reports how many times a coupon has already been redeemed.
+type RedemptionCounter interface {
+	CountRedemptions(ctx context.Context, couponCode string) (int64, error)
+	CountCustomerRedemptions(ctx context.Context, couponCode, customerID string) (int64, error)
+}
+
+// OrderHistory reports how many orders a customer has already placed.
+type OrderHistory interface {
+	CountCustomerOrders(ctx context.Context, customerID string) (int64, error)
+}
+
+// DiscountLedger reports the discount coupons have given so far, to check it
+// against their budgets. The discount of a coupon includes the discount of
+// the codes generated from it.
+type DiscountLedger interface {
+	SumCouponDiscounts(ctx context.Context, couponCode string) (money.Amount, error)
+	SumCampaignDiscounts(ctx context.Context, campaignID uint64) (money.Amount, error)
+}
+
+// Wallets looks up the coupons customers claimed into their wallet.
+type Wallets interface {
+	GetWalletCoupon(ctx context.Context, customerID, couponCode string) (model.WalletCoupon, error)
+}
+
+// Campaigns looks up the campaign a coupon belongs to.
+type Campaigns interface {
+	GetCampaignByID(ctx context.Context, id uint64) (model.Campaign, error)
+}
+
+// CustomerSegments reports whether a customer belongs to a segment.
+type CustomerSegments interface {
+	IsCustomerInSegment(ctx context.Context, customerID, segment string) (bool, error)
+}
+
+// CouponServiceDeps are the lookups the coupon service checks orders against.
+// Lookups a caller leaves nil must not be needed by the coupons it checks.
+// Discounts defaults to DefaultDiscountRegistry.
+type CouponServiceDeps struct {
+	Redemptions  RedemptionCounter
+	OrderHistory OrderHistory
+	Segments     CustomerSegments
+	Campaigns    Campaigns
+	Ledger       DiscountLedger
+	Wallets      Wallets
+	Discounts    *DiscountRegistry
 }
 
 type couponServiceImpl struct {
-	l logger.Interface
+	l  logger.Interface
+	rc RedemptionCounter
+	oh OrderHistory
+	sg CustomerSegments
+	cp Campaigns
+	dl DiscountLedger
+	wl Wallets
+	dr *DiscountRegistry
 }
 
-func NewCouponService(l logger.Interface) CouponService {
+func NewCouponService(l logger.Interface, deps CouponServiceDeps) CouponService {
+	if deps.Discounts == nil {
+		deps.Discounts = DefaultDiscountRegistry()
+	}
 	return &couponServiceImpl{
-		l: l,
+		l:  l,
+		rc: deps.Redemptions,
+		oh: deps.OrderHistory,
+		sg: deps.Segments,
+		cp: deps.Campaigns,
+		dl: deps.Ledger,
+		wl: deps.Wallets,
+		dr: deps.Discounts,
 	}
 }
 
 func (c *couponServiceImpl) ValidateCoupon(ctx context.Context, coupon model.Coupon, req schema.CreateMockOrderRequest) (bool, error) {
+	if coupon.Status != model.CouponStatusActive {
+		return false, fmt.Errorf("coupon %s is %s", coupon.CouponCode, coupon.Status)
+	}
+	if coupon.StartsAt != nil && req.CreatedAt.Before(*coupon.StartsAt) {
+		return false, fmt.Errorf("coupon %s is not active until %s", coupon.CouponCode, coupon.StartsAt.Format(time.RFC3339))
+	}
 	if req.CreatedAt.After(coupon.ExpiredAt) {
 		return false, fmt.Errorf("coupon %s is expired", coupon.CouponCode)
 	}
+	if err := validateWindows(coupon, req.CreatedAt); err != nil {
+		return false, err
+	}
+	if err := c.validateCampaign(ctx, coupon, req); err != nil {
+		return false, err
+	}
+	if coupon.Currency.OrDefault() != req.OrderCurrency() {
+		return false, fmt.Errorf("coupon %s is in %s and cannot be applied to a %s order", coupon.CouponCode, coupon.Currency.OrDefault(), req.OrderCurrency())
+	}
+	if err := c.validateCustomer(ctx, coupon, req); err != nil {
+		return false, err
+	}
+	if err := c.validateWallet(ctx, coupon, req); err != nil {
+		return false, err
+	}
+	if err := c.validateFirstOrder(ctx, coupon, req); err != nil {
+		return false, err
+	}
+	if err := validateChannel(coupon, req); err != nil {
+		return false, err
+	}
+	if coupon.MinOrderAmount != nil && req.Cost.LessThan(*coupon.MinOrderAmount) {
+		return false, minOrderAmountError(coupon, req.Cost)
+	}
+	if err := validateTargeting(coupon, req); err != nil {
+		return false, err
+	}
+	calculator, err := c.dr.Get(coupon.CouponType)
+	if err != nil {
+		return false, err
+	}
+	if err := calculator.CheckOrder(coupon, req); err != nil {
+		return false, err
+	}
+	if err := c.validateRedemptionLimits(ctx, coupon, req); err != nil {
+		return false, err
+	}
+	if err := c.validateBudget(ctx, coupon, req); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
-func (c *couponServiceImpl) CalculateAmount(ctx context.Context, coupon *model.Coupon, amount float64) (float64, error) {
+// ValidateCouponParams checks the coupon's parameters against the rules of
+// its type.
+func (c *couponServiceImpl) ValidateCouponParams(ctx context.Context, coupon model.Coupon) error {
+	if _, err := coupon.Location(); err != nil {
+		return fmt.Errorf("invalid time_zone: %w", err)
+	}
+	for i, window := range coupon.ValidityWindows {
+		if err := window.Validate(); err != nil {
+			return fmt.Errorf("validity window %d: %w", i+1, err)
+		}
+	}
+	if coupon.ClaimLimit != nil && !coupon.RequiresClaim {
+		return fmt.Errorf("claim_limit requires requires_claim to be set")
+	}
+	if err := validateAmounts(coupon); err != nil {
+		return err
+	}
+	calculator, err := c.dr.Get(coupon.CouponType)
+	if err != nil {
+		return err
+	}
+	return calculator.Validate(coupon)
+}
+
+// validateAmounts rejects order amount rules and budgets with more decimals
+// than the coupon's currency, which would otherwise be rounded when stored.
+func validateAmounts(coupon model.Coupon) error {
+	amounts := []struct {
+		name   string
+		amount *money.Amount
+	}{
+		{"min_order_amount", coupon.MinOrderAmount},
+		{"max_discount_amount", coupon.MaxDiscountAmount},
+		{"budget", coupon.Budget},
+	}
+	for _, a := range amounts {
+		if a.amount != nil && !a.amount.FitsMinorUnit(coupon.Currency) {
+			return fmt.Errorf("%s %s has more decimals than %s allows", a.name, a.amount, coupon.Currency.OrDefault())
+		}
+	}
+	return nil
+}
+
+func (c *couponServiceImpl) ValidateStatusTransition(ctx context.Context, coupon model.Coupon, status model.CouponStatus) error {
+	for _, next := range couponStatusTransitions[coupon.Status] {
+		if next == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("coupon %s cannot move from %s to %s", coupon.CouponCode, coupon.Status, status)
+}
+
+// RankCoupons evaluates every coupon against the order and returns the ones
+// it qualifies for, ordered by the amount they take off. Ties are broken by
+// the earliest expiry and then by coupon code.
+func (c *couponServiceImpl) RankCoupons(ctx context.Context, coupons []model.Coupon, req schema.CreateMockOrderRequest) []CouponSelection {
+	eligible := make([]CouponSelection, 0, len(coupons))
+	for _, coupon := range coupons {
+		if _, err := c.ValidateCoupon(ctx, coupon, req); err != nil {
+			c.l.Debug("Coupon is not eligible", "coupon_code", coupon.CouponCode, "reason", err.Error())
+			continue
+		}
+		capped, err := c.withBudgetCap(ctx, coupon)
+		if err != nil {
+			c.l.Debug("Coupon cannot be applied", "coupon_code", coupon.CouponCode, "reason", err.Error())
+			continue
+		}
+		amounts, tier, err := c.applyDiscount(capped, orderAmounts(req))
+		if err != nil {
+			c.l.Debug("Coupon cannot be applied", "coupon_code", coupon.CouponCode, "reason", err.Error())
+			continue
+		}
+		eligible = append(eligible, CouponSelection{
+			Coupon:      coupon,
+			Amounts:     amounts,
+			TotalAmount: amounts.Total(),
+			Tier:        tier,
+		})
+	}
+
+	sort.SliceStable(eligible, func(i, j int) bool {
+		if cmp := eligible[i].TotalAmount.Cmp(eligible[j].TotalAmount); cmp != 0 {
+			return cmp < 0
+		}
+		if !eligible[i].Coupon.ExpiredAt.Equal(eligible[j].Coupon.ExpiredAt) {
+			return eligible[i].Coupon.ExpiredAt.Before(eligible[j].Coupon.ExpiredAt)
+		}
+		return eligible[i].Coupon.CouponCode < eligible[j].Coupon.CouponCode
+	})
+	return eligible
+}
+
+// SelectAutoCoupon returns the auto coupon that takes the most off the order,
+// or nil if none qualifies.
+func (c *couponServiceImpl) SelectAutoCoupon(ctx context.Context, coupons []model.Coupon, req schema.CreateMockOrderRequest) (*CouponSelection, error) {
+	autoCoupons := make([]model.Coupon, 0, len(coupons))
+	for _, coupon := range coupons {
+		if coupon.Usage == model.CouponUsageAuto {
+			autoCoupons = append(autoCoupons, coupon)
+		}
+	}
+	eligible := c.RankCoupons(ctx, autoCoupons, req)
+	if len(eligible) == 0 {
+		return nil, nil
+	}
+
+	best := eligible[0]
+	discount := req.OrderTotal().Sub(best.TotalAmount)
+	if len(eligible) == 1 {
+		best.Reason = fmt.Sprintf("coupon %s was applied automatically as the only eligible auto coupon, saving %s", best.Coupon.CouponCode, discount.StringFixed())
+	} else {
+		best.Reason = fmt.Sprintf("coupon %s was applied automatically as the best of %d eligible auto coupons, saving %s", best.Coupon.CouponCode, len(eligible), discount.StringFixed())
+	}
+	return &best, nil
+}
+
+// ValidateClaim checks that a customer may claim the coupon into their wallet
+// at the given time. Coupons can be claimed before they start, but not once
+// they expired or stopped being active.
+func (c *couponServiceImpl) ValidateClaim(ctx context.Context, coupon model.Coupon, customerID string, at time.Time) error {
+	if coupon.Status != model.CouponStatusActive {
+		return fmt.Errorf("coupon %s is %s", coupon.CouponCode, coupon.Status)
+	}
+	if !at.Before(coupon.ExpiredAt) {
+		return fmt.Errorf("coupon %s is expired", coupon.CouponCode)
+	}
+	return c.validateCustomer(ctx, coupon, schema.CreateMockOrderRequest{CustomerID: &customerID, CreatedAt: at})
+}
+
+// validateWallet rejects coupons that must be claimed unless the customer
+// holds an unexpired claim on them.
+func (c *couponServiceImpl) validateWallet(ctx context.Context, coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if !coupon.RequiresClaim {
+		return nil
+	}
+	if req.CustomerID == nil {
+		return fmt.Errorf("coupon %s must be claimed and requires a customer_id", coupon.CouponCode)
+	}
+	wallet, err := c.wl.GetWalletCoupon(ctx, *req.CustomerID, coupon.CouponCode)
+	if err != nil {
+		if _, ok := err.(errs.NotFoundError); ok {
+			return fmt.Errorf("coupon %s must be claimed into the wallet of customer %s first", coupon.CouponCode, *req.CustomerID)
+		}
+		c.l.Error("Failed to get wallet coupon", "error", err, "coupon_code", coupon.CouponCode, "customer_id", *req.CustomerID)
+		return fmt.Errorf("failed to check the wallet of customer %s", *req.CustomerID)
+	}
+	if wallet.Expired(req.CreatedAt) {
+		return fmt.Errorf("the claim of customer %s on coupon %s expired at %s", *req.CustomerID, coupon.CouponCode, wallet.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// validateCustomer rejects orders from customers the coupon is not meant for.
+// A coupon restricted to both an allow-list and a segment is available to
+// customers on the list and to members of the segment.
+func (c *couponServiceImpl) validateCustomer(ctx context.Context, coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if coupon.AllowedCustomerIDs == nil && coupon.CustomerSegment == nil {
+		return nil
+	}
+	if req.CustomerID == nil {
+		return fmt.Errorf("coupon %s requires a customer_id", coupon.CouponCode)
+	}
+	if coupon.AllowedCustomerIDs.Contains(*req.CustomerID) {
+		return nil
+	}
+	if coupon.CustomerSegment != nil {
+		member, err := c.sg.IsCustomerInSegment(ctx, *req.CustomerID, *coupon.CustomerSegment)
+		if err != nil {
+			c.l.Error("Failed to check customer segment", "error", err, "coupon_code", coupon.CouponCode, "segment", *coupon.CustomerSegment)
+			return fmt.Errorf("failed to check eligibility for coupon %s", coupon.CouponCode)
+		}
+		if member {
+			return nil
+		}
+	}
+	return fmt.Errorf("coupon %s is not available to customer %s", coupon.CouponCode, *req.CustomerID)
+}
+
+// validateCampaign rejects orders placed outside the dates of the coupon's
+// campaign.
+func (c *couponServiceImpl) validateCampaign(ctx context.Context, coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if coupon.CampaignID == nil {
+		return nil
+	}
+	campaign, err := c.cp.GetCampaignByID(ctx, *coupon.CampaignID)
+	if err != nil {
+		c.l.Error("Failed to get coupon campaign", "error", err, "coupon_code", coupon.CouponCode, "campaign_id", *coupon.CampaignID)
+		return fmt.Errorf("failed to check the campaign of coupon %s", coupon.CouponCode)
+	}
+	if req.CreatedAt.Before(campaign.StartsAt) {
+		return fmt.Errorf("campaign %s of coupon %s does not start until %s", campaign.Name, coupon.CouponCode, campaign.StartsAt.Format(time.RFC3339))
+	}
+	if req.CreatedAt.After(campaign.EndsAt) {
+		return fmt.Errorf("campaign %s of coupon %s has ended", campaign.Name, coupon.CouponCode)
+	}
+	return nil
+}
+
+// validateFirstOrder rejects first-order-only coupons for customers who have
+// already placed an order. The order repository re-checks it when the order
+// is persisted.
+func (c *couponServiceImpl) validateFirstOrder(ctx context.Context, coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if !coupon.FirstOrderOnly {
+		return nil
+	}
+	if req.CustomerID == nil {
+		return fmt.Errorf("coupon %s requires a customer_id", coupon.CouponCode)
+	}
+	orders, err := c.oh.CountCustomerOrders(ctx, *req.CustomerID)
+	if err != nil {
+		c.l.Error("Failed to count customer orders", "error", err, "coupon_code", coupon.CouponCode)
+		return fmt.Errorf("failed to check order history for coupon %s", coupon.CouponCode)
+	}
+	if orders > 0 {
+		return fmt.Errorf("coupon %s is only valid on a first order and customer %s already has %d orders", coupon.CouponCode, *req.CustomerID, orders)
+	}
+	return nil
+}
+
+// validateRedemptionLimits gives an early answer on usage limits. The order
+// repository re-checks them under a row lock before an order is persisted.
+func (c *couponServiceImpl) validateRedemptionLimits(ctx context.Context, coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if coupon.MaxRedemptions != nil {
+		total, err := c.rc.CountRedemptions(ctx, coupon.CouponCode)
+		if err != nil {
+			c.l.Error("Failed to count coupon redemptions", "error", err, "coupon_code", coupon.CouponCode)
+			return fmt.Errorf("failed to check usage of coupon %s", coupon.CouponCode)
+		}
+		if total >= int64(*coupon.MaxRedemptions) {
+			return fmt.Errorf("coupon %s has reached its redemption limit", coupon.CouponCode)
+		}
+	}
+	if coupon.MaxRedemptionsPerCustomer != nil {
+		if req.CustomerID == nil {
+			return fmt.Errorf("coupon %s requires a customer_id", coupon.CouponCode)
+		}
+		used, err := c.rc.CountCustomerRedemptions(ctx, coupon.CouponCode, *req.CustomerID)
+		if err != nil {
+			c.l.Error("Failed to count customer redemptions", "error", err, "coupon_code", coupon.CouponCode)
+			return fmt.Errorf("failed to check usage of coupon %s", coupon.CouponCode)
+		}
+		if used >= int64(*coupon.MaxRedemptionsPerCustomer) {
+			return fmt.Errorf("customer %s has reached the redemption limit of coupon %s", *req.CustomerID, coupon.CouponCode)
+		}
+	}
+	return nil
+}
+
+func (c *couponServiceImpl) CalculateAmount(ctx context.Context, coupon *model.Coupon, amount money.Amount) (money.Amount, error) {
 	// Not implemented yet
 	if coupon == nil {
-		c.l.Error("Coupon is nil", "amount", amount)
+		c.l.Error("Coupon is nil", "amount", amount.String())
 		return amount, nil
 	}
-	switch (*coupon).CouponType {
-	case "fixed":
-		return handleFixedCoupon(*coupon, amount)
-	case "percentage":
-		return handlePercentageCoupon(*coupon, amount)
-	default:
+	if coupon.MinOrderAmount != nil && amount.LessThan(*coupon.MinOrderAmount) {
+		return amount, minOrderAmountError(*coupon, amount)
+	}
+	amounts, _, err := c.applyDiscount(*coupon, OrderAmounts{Subtotal: amount})
+	if err != nil {
+		return money.Zero, err
+	}
+	return amounts.Subtotal, nil
+}
+
+// ApplyCoupons checks that the coupons may be combined, validates each of
+// them against the order and applies them in stacking order, each one to the
+// amount left by the previous one.
+func (c *couponServiceImpl) ApplyCoupons(ctx context.Context, coupons []model.Coupon, req schema.CreateMockOrderRequest) ([]AppliedCoupon, OrderAmounts, error) {
+	if err := validateStacking(coupons); err != nil {
+		return nil, OrderAmounts{}, err
+	}
+	for _, coupon := range coupons {
+		if _, err := c.ValidateCoupon(ctx, coupon, req); err != nil {
+			return nil, OrderAmounts{}, err
+		}
+	}
+
+	amounts := orderAmounts(req)
+	applied := make([]AppliedCoupon, 0, len(coupons))
+	for _, coupon := range stackingOrder(coupons) {
+		capped, err := c.withBudgetCap(ctx, coupon)
+		if err != nil {
+			return nil, OrderAmounts{}, err
+		}
+		discounted, tier, err := c.applyDiscount(capped, amounts)
+		if err != nil {
+			return nil, OrderAmounts{}, err
+		}
+		applied = append(applied, AppliedCoupon{
+			Coupon:         coupon,
+			DiscountAmount: amounts.Total().Sub(discounted.Total()),
+			TotalAmount:    discounted.Total(),
+			Tier:           tier,
+		})
+		amounts = discounted
+	}
+	return applied, amounts, nil
+}
+
+// validateBudget rejects orders whose discount would go over what is left of
+// the budget of the coupon or of its campaign. Coupons that allow a partial
+// discount only need some budget left; withBudgetCap then limits their
+// discount to it. The order repository re-checks budgets when the order is
+// persisted.
+func (c *couponServiceImpl) validateBudget(ctx context.Context, coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	remaining, err := c.remainingBudget(ctx, coupon)
+	if err != nil || remaining == nil {
+		return err
+	}
+	if !remaining.GreaterThan(money.Zero) {
+		return fmt.Errorf("coupon %s has used up its budget", coupon.CouponCode)
+	}
+	if coupon.AllowPartialDiscount {
+		return nil
+	}
+	amounts := orderAmounts(req)
+	discounted, _, err := c.applyDiscount(coupon, amounts)
+	if err != nil {
+		return err
+	}
+	if discount := amounts.Total().Sub(discounted.Total()); discount.GreaterThan(*remaining) {
+		return fmt.Errorf("coupon %s has %s of its budget left and cannot give a discount of %s", coupon.CouponCode, remaining.StringFixed(), discount.StringFixed())
+	}
+	return nil
+}
+
+// withBudgetCap lowers the MaxDiscountAmount of coupons that allow a partial
+// discount to what is left of their budget.
+func (c *couponServiceImpl) withBudgetCap(ctx context.Context, coupon model.Coupon) (model.Coupon, error) {
+	if !coupon.AllowPartialDiscount {
+		return coupon, nil
+	}
+	remaining, err := c.remainingBudget(ctx, coupon)
+	if err != nil || remaining == nil {
+		return coupon, err
+	}
+	if coupon.MaxDiscountAmount == nil || remaining.LessThan(*coupon.MaxDiscountAmount) {
+		coupon.MaxDiscountAmount = remaining
+	}
+	return coupon, nil
+}
+
+// remainingBudget returns what is left of the budget of the coupon or of its
+// campaign, whichever is lower, or nil if neither has a budget.
+func (c *couponServiceImpl) remainingBudget(ctx context.Context, coupon model.Coupon) (*money.Amount, error) {
+	var remaining *money.Amount
+	if coupon.Budget != nil {
+		used, err := c.dl.SumCouponDiscounts(ctx, coupon.BudgetCode())
+		if err != nil {
+			c.l.Error("Failed to sum coupon discounts", "error", err, "coupon_code", coupon.BudgetCode())
+			return nil, fmt.Errorf("failed to check the budget of coupon %s", coupon.CouponCode)
+		}
+		left := money.Max(coupon.Budget.Sub(used), money.Zero)
+		remaining = &left
+	}
+	if coupon.CampaignID == nil {
+		return remaining, nil
+	}
+	campaign, err := c.cp.GetCampaignByID(ctx, *coupon.CampaignID)
+	if err != nil {
+		c.l.Error("Failed to get coupon campaign", "error", err, "coupon_code", coupon.CouponCode, "campaign_id", *coupon.CampaignID)
+		return nil, fmt.Errorf("failed to check the campaign of coupon %s", coupon.CouponCode)
+	}
+	if campaign.Budget == nil {
+		return remaining, nil
+	}
+	used, err := c.dl.SumCampaignDiscounts(ctx, campaign.ID)
+	if err != nil {
+		c.l.Error("Failed to sum campaign discounts", "error", err, "campaign_id", campaign.ID)
+		return nil, fmt.Errorf("failed to check the budget of campaign %s", campaign.Name)
+	}
+	left := money.Max(campaign.Budget.Sub(used), money.Zero)
+	if remaining == nil || left.LessThan(*remaining) {
+		remaining = &left
+	}
+	return remaining, nil
+}
+
+// applyDiscount takes the coupon's discount off the part of the order it
+// applies to, without checking whether the order qualifies for it. It also
+// returns the 1-based level of the tier the discount was taken from, 0 for
+// coupons without tiers.
+func (c *couponServiceImpl) applyDiscount(coupon model.Coupon, amounts OrderAmounts) (OrderAmounts, int, error) {
+	calculator, err := c.dr.Get(coupon.CouponType)
+	if err != nil {
 		c.l.Error("Invalid coupon type", "coupon_type", coupon.CouponType)
-		return 0, fmt.Errorf("invalid coupon type: %s", coupon.CouponType)
+		return OrderAmounts{}, 0, err
 	}
+	targeted := amounts.targeted(coupon)
+	tier := 0
+	if tiered, ok := calculator.(TieredCalculator); ok {
+		tier = tiered.SelectTier(coupon, targeted) + 1
+	}
+	discounted, err := calculator.Apply(coupon, targeted)
+	if err != nil {
+		return OrderAmounts{}, 0, err
+	}
+	return amounts.withDiscount(coupon, targeted, discounted), tier, nil
+}
+
+// validateStacking rejects duplicate coupons, exclusive coupons combined with
+// others and coupons sharing a stacking group.
+func validateStacking(coupons []model.Coupon) error {
+	seen := make(map[string]bool, len(coupons))
+	groups := make(map[string]string, len(coupons))
+	for _, coupon := range coupons {
+		if seen[coupon.CouponCode] {
+			return fmt.Errorf("coupon %s was applied more than once", coupon.CouponCode)
+		}
+		seen[coupon.CouponCode] = true
+		if coupon.Exclusive && len(coupons) > 1 {
+			return fmt.Errorf("coupon %s cannot be combined with other coupons", coupon.CouponCode)
+		}
+		if coupon.StackingGroup == nil {
+			continue
+		}
+		if other, ok := groups[*coupon.StackingGroup]; ok {
+			return fmt.Errorf("coupons %s and %s belong to stacking group %s and cannot be combined", other, coupon.CouponCode, *coupon.StackingGroup)
+		}
+		groups[*coupon.StackingGroup] = coupon.CouponCode
+	}
+	return nil
 }
 
-func handleFixedCoupon(coupon model.Coupon, amount float64) (float64, error) {
-	discountedAmount := amount - coupon.CouponValue
-	if discountedAmount < 0 {
-		return 0, nil // Ensure the total does not go below zero
+// stackingOrder sorts coupons by priority, then applies fixed coupons before
+// percentage ones, then falls back to the coupon code.
+func stackingOrder(coupons []model.Coupon) []model.Coupon {
+	ordered := make([]model.Coupon, len(coupons))
+	copy(ordered, coupons)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority < ordered[j].Priority
+		}
+		if ordered[i].CouponType != ordered[j].CouponType {
+			return ordered[i].CouponType == model.CouponTypeFixed
+		}
+		return ordered[i].CouponCode < ordered[j].CouponCode
+	})
+	return ordered
+}
+
+// validateWindows rejects orders placed outside the coupon's validity windows,
+// read as wall clock times in the coupon's time zone.
+func validateWindows(coupon model.Coupon, at time.Time) error {
+	if len(coupon.ValidityWindows) == 0 {
+		return nil
+	}
+	location, err := coupon.Location()
+	if err != nil {
+		return fmt.Errorf("coupon %s has an invalid time zone: %w", coupon.CouponCode, err)
+	}
+	local := at.In(location)
+	if !coupon.ValidityWindows.Contains(local) {
+		return fmt.Errorf("coupon %s is not valid on %s at %s (%s)", coupon.CouponCode, local.Weekday(), local.Format("15:04"), location)
 	}
-	return discountedAmount, nil
+	return nil
 }
 
-func handlePercentageCoupon(coupon model.Coupon, amount float64) (float64, error) {
-	discount := (coupon.CouponValue / 100) * amount
-	discountedAmount := amount - discount
-	if discountedAmount < 0 {
-		return 0, nil // Ensure the total does not go below zero
+// validateChannel rejects orders placed on a channel or paid with a payment
+// method the coupon is not offered for. Empty lists do not restrict anything.
+func validateChannel(coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if len(coupon.Channels) > 0 {
+		if req.Channel == nil {
+			return fmt.Errorf("coupon %s is only valid on channels %s and the order has no channel", coupon.CouponCode, strings.Join(coupon.Channels, ", "))
+		}
+		if !coupon.Channels.Contains(*req.Channel) {
+			return fmt.Errorf("coupon %s is not valid on channel %s", coupon.CouponCode, *req.Channel)
+		}
 	}
-	return discountedAmount, nil
+	if len(coupon.PaymentMethods) > 0 {
+		if req.PaymentMethod == nil {
+			return fmt.Errorf("coupon %s is only valid with payment methods %s and the order has no payment method", coupon.CouponCode, strings.Join(coupon.PaymentMethods, ", "))
+		}
+		if !coupon.PaymentMethods.Contains(*req.PaymentMethod) {
+			return fmt.Errorf("coupon %s is not valid with payment method %s", coupon.CouponCode, *req.PaymentMethod)
+		}
+	}
+	return nil
+}
+
+// validateTargeting rejects orders without a single item the coupon may
+// discount.
+func validateTargeting(coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if coupon.Targeting == nil || coupon.Targeting.IsEmpty() {
+		return nil
+	}
+	if len(req.Items) == 0 {
+		return fmt.Errorf("coupon %s only applies to some products and the order has no items", coupon.CouponCode)
+	}
+	for _, item := range req.Items {
+		if coupon.Targeting.Matches(item.SKU, item.Category) {
+			return nil
+		}
+	}
+	return fmt.Errorf("none of the order items are eligible for coupon %s", coupon.CouponCode)
+}
+
+func minOrderAmountError(coupon model.Coupon, amount money.Amount) error {
+	return fmt.Errorf("order amount %s does not qualify for coupon %s: a minimum order of %s is required", amount.StringFixed(), coupon.CouponCode, coupon.MinOrderAmount.StringFixed())
 }
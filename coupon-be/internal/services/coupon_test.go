@@ -5,14 +5,105 @@ import (
 	"coupon-be/internal/model"
 	"coupon-be/internal/schema"
 	"coupon-be/pkg/logger"
+	"coupon-be/pkg/money"
+	"coupon-be/pkg/utils/errs"
+	"fmt"
 	"testing"
 	"time"
 )
 
+type fakeRedemptionCounter struct {
+	total    int64
+	customer map[string]int64
+}
+
+func (f fakeRedemptionCounter) CountRedemptions(ctx context.Context, couponCode string) (int64, error) {
+	return f.total, nil
+}
+
+func (f fakeRedemptionCounter) CountCustomerRedemptions(ctx context.Context, couponCode, customerID string) (int64, error) {
+	return f.customer[customerID], nil
+}
+
+// fakeOrderHistory maps customers to the number of orders they placed.
+type fakeOrderHistory map[string]int64
+
+func (f fakeOrderHistory) CountCustomerOrders(ctx context.Context, customerID string) (int64, error) {
+	return f[customerID], nil
+}
+
+// fakeCampaigns maps campaign IDs to campaigns.
+type fakeCampaigns map[uint64]model.Campaign
+
+func (f fakeCampaigns) GetCampaignByID(ctx context.Context, id uint64) (model.Campaign, error) {
+	campaign, ok := f[id]
+	if !ok {
+		return model.Campaign{}, fmt.Errorf("campaign %d not found", id)
+	}
+	return campaign, nil
+}
+
+// fakeDiscountLedger holds the discount coupons and campaigns have given.
+type fakeDiscountLedger struct {
+	coupons   map[string]money.Amount
+	campaigns map[uint64]money.Amount
+}
+
+func (f fakeDiscountLedger) SumCouponDiscounts(ctx context.Context, couponCode string) (money.Amount, error) {
+	if used, ok := f.coupons[couponCode]; ok {
+		return used, nil
+	}
+	return money.Zero, nil
+}
+
+func (f fakeDiscountLedger) SumCampaignDiscounts(ctx context.Context, campaignID uint64) (money.Amount, error) {
+	if used, ok := f.campaigns[campaignID]; ok {
+		return used, nil
+	}
+	return money.Zero, nil
+}
+
+// fakeWallets maps customers to the claims in their wallet.
+type fakeWallets map[string][]model.WalletCoupon
+
+func (f fakeWallets) GetWalletCoupon(ctx context.Context, customerID, couponCode string) (model.WalletCoupon, error) {
+	for _, wallet := range f[customerID] {
+		if wallet.CouponCode == couponCode {
+			return wallet, nil
+		}
+	}
+	return model.WalletCoupon{}, errs.NotFoundError{Message: "not in wallet"}
+}
+
+// fakeCustomerSegments maps segments to the customers in them.
+type fakeCustomerSegments map[string][]string
+
+func (f fakeCustomerSegments) IsCustomerInSegment(ctx context.Context, customerID, segment string) (bool, error) {
+	for _, id := range f[segment] {
+		if id == customerID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func TestValidateCoupon(t *testing.T) {
 	logger := logger.New("test")
-	cs := NewCouponService(logger)
+	cs := NewCouponService(logger, CouponServiceDeps{
+		Redemptions: fakeRedemptionCounter{
+			total:    5,
+			customer: map[string]int64{"CUSTOMER1": 2},
+		},
+		OrderHistory: fakeOrderHistory{"CUSTOMER1": 3},
+		Segments:     fakeCustomerSegments{"vip": {"CUSTOMER2"}},
+	})
 	testString := "TEST123"
+	customerID := "CUSTOMER1"
+	newCustomerID := "CUSTOMER2"
+	five, ten, two := 5, 10, 2
+	minOrderAmount := money.New(200000)
+	startsAt := time.Now().Add(24 * time.Hour)
+	vip := "vip"
 	tests := []struct {
 		name   string
 		coupon model.Coupon
@@ -26,15 +117,16 @@ func TestValidateCoupon(t *testing.T) {
 				Title:       "Test Coupon",
 				Description: "This is a test coupon",
 				CouponType:  "fixed",
+				Status:      "active",
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(24 * time.Hour),
-				CouponValue: 15000,
+				CouponValue: money.New(15000),
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
 			req: schema.CreateMockOrderRequest{
 				CouponCode: &testString,
-				Cost:       100000,
+				Cost:       money.New(100000),
 				CreatedAt:  time.Now(),
 			},
 			want: true,
@@ -46,15 +138,16 @@ func TestValidateCoupon(t *testing.T) {
 				Title:       "Expired Coupon",
 				Description: "This coupon is expired",
 				CouponType:  "fixed",
+				Status:      "active",
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(-24 * time.Hour), // Expired
-				CouponValue: 15000,
+				CouponValue: money.New(15000),
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
 			req: schema.CreateMockOrderRequest{
 				CouponCode: &testString,
-				Cost:       100000,
+				Cost:       money.New(100000),
 				CreatedAt:  time.Now(),
 			},
 			want: false,
@@ -66,26 +159,376 @@ func TestValidateCoupon(t *testing.T) {
 				Title:       "Test Coupon",
 				Description: "This is a test coupon",
 				CouponType:  "percentage",
+				Status:      "active",
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(24 * time.Hour),
-				CouponValue: 20, // 20% discount
+				CouponValue: money.New(20), // 20% discount
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
 			req: schema.CreateMockOrderRequest{
 				CouponCode: &testString,
-				Cost:       50000,
+				Cost:       money.New(50000),
+				CreatedAt:  time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "TC1.4: Coupon Reached Redemption Limit",
+			coupon: model.Coupon{
+				CouponCode:     testString,
+				CouponType:     "fixed",
+				Status:         "active",
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponValue:    money.New(15000),
+				MaxRedemptions: &five,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.5: Coupon Below Redemption Limit",
+			coupon: model.Coupon{
+				CouponCode:     testString,
+				CouponType:     "fixed",
+				Status:         "active",
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponValue:    money.New(15000),
+				MaxRedemptions: &ten,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "TC1.6: Customer Reached Per-Customer Limit",
+			coupon: model.Coupon{
+				CouponCode:                testString,
+				CouponType:                "fixed",
+				Status:                    "active",
+				ExpiredAt:                 time.Now().Add(24 * time.Hour),
+				CouponValue:               money.New(15000),
+				MaxRedemptionsPerCustomer: &two,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				CustomerID: &customerID,
+				Cost:       money.New(100000),
 				CreatedAt:  time.Now(),
 			},
+			want: false,
+		},
+		{
+			name: "TC1.7: New Customer Within Per-Customer Limit",
+			coupon: model.Coupon{
+				CouponCode:                testString,
+				CouponType:                "fixed",
+				Status:                    "active",
+				ExpiredAt:                 time.Now().Add(24 * time.Hour),
+				CouponValue:               money.New(15000),
+				MaxRedemptionsPerCustomer: &two,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				CustomerID: &newCustomerID,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "TC1.8: Per-Customer Limit Without Customer",
+			coupon: model.Coupon{
+				CouponCode:                testString,
+				CouponType:                "fixed",
+				Status:                    "active",
+				ExpiredAt:                 time.Now().Add(24 * time.Hour),
+				CouponValue:               money.New(15000),
+				MaxRedemptionsPerCustomer: &two,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.9: Order Below Minimum Amount",
+			coupon: model.Coupon{
+				CouponCode:     testString,
+				CouponType:     "fixed",
+				Status:         "active",
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponValue:    money.New(15000),
+				MinOrderAmount: &minOrderAmount,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.10: Order Meets Minimum Amount",
+			coupon: model.Coupon{
+				CouponCode:     testString,
+				CouponType:     "fixed",
+				Status:         "active",
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponValue:    money.New(15000),
+				MinOrderAmount: &minOrderAmount,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(200000),
+				CreatedAt:  time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "TC1.11: Coupon Not Started Yet",
+			coupon: model.Coupon{
+				CouponCode:  testString,
+				CouponType:  "fixed",
+				Status:      "active",
+				StartsAt:    &startsAt,
+				ExpiredAt:   time.Now().Add(48 * time.Hour),
+				CouponValue: money.New(15000),
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.12: Coupon Already Started",
+			coupon: model.Coupon{
+				CouponCode:  testString,
+				CouponType:  "fixed",
+				Status:      "active",
+				StartsAt:    &startsAt,
+				ExpiredAt:   time.Now().Add(48 * time.Hour),
+				CouponValue: money.New(15000),
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  startsAt.Add(time.Hour),
+			},
 			want: true,
 		},
+		{
+			name: "TC1.13: Paused Coupon",
+			coupon: model.Coupon{
+				CouponCode:  testString,
+				CouponType:  "fixed",
+				Status:      "paused",
+				ExpiredAt:   time.Now().Add(24 * time.Hour),
+				CouponValue: money.New(15000),
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.14: Coupon in Another Currency",
+			coupon: model.Coupon{
+				CouponCode:  testString,
+				CouponType:  "fixed",
+				Status:      "active",
+				ExpiredAt:   time.Now().Add(24 * time.Hour),
+				CouponValue: money.New(15000),
+				Currency:    "VND",
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100),
+				Currency:   "USD",
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.15: Coupon in Order Currency",
+			coupon: model.Coupon{
+				CouponCode:  testString,
+				CouponType:  "fixed",
+				Status:      "active",
+				ExpiredAt:   time.Now().Add(24 * time.Hour),
+				CouponValue: money.New(5),
+				Currency:    "USD",
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100),
+				Currency:   "USD",
+				CreatedAt:  time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "TC1.16: Customer on Allow-List",
+			coupon: model.Coupon{
+				CouponCode:         testString,
+				CouponType:         "fixed",
+				Status:             "active",
+				ExpiredAt:          time.Now().Add(24 * time.Hour),
+				CouponValue:        money.New(10000),
+				AllowedCustomerIDs: model.StringList{customerID},
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				CustomerID: &customerID,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "TC1.17: Customer not on Allow-List",
+			coupon: model.Coupon{
+				CouponCode:         testString,
+				CouponType:         "fixed",
+				Status:             "active",
+				ExpiredAt:          time.Now().Add(24 * time.Hour),
+				CouponValue:        money.New(10000),
+				AllowedCustomerIDs: model.StringList{customerID},
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				CustomerID: &newCustomerID,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.18: Restricted Coupon without Customer",
+			coupon: model.Coupon{
+				CouponCode:         testString,
+				CouponType:         "fixed",
+				Status:             "active",
+				ExpiredAt:          time.Now().Add(24 * time.Hour),
+				CouponValue:        money.New(10000),
+				AllowedCustomerIDs: model.StringList{customerID},
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.19: Customer in Segment",
+			coupon: model.Coupon{
+				CouponCode:      testString,
+				CouponType:      "fixed",
+				Status:          "active",
+				ExpiredAt:       time.Now().Add(24 * time.Hour),
+				CouponValue:     money.New(10000),
+				CustomerSegment: &vip,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				CustomerID: &newCustomerID,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "TC1.20: Customer outside Segment",
+			coupon: model.Coupon{
+				CouponCode:      testString,
+				CouponType:      "fixed",
+				Status:          "active",
+				ExpiredAt:       time.Now().Add(24 * time.Hour),
+				CouponValue:     money.New(10000),
+				CustomerSegment: &vip,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				CustomerID: &customerID,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.21: First Order Only for New Customer",
+			coupon: model.Coupon{
+				CouponCode:     testString,
+				CouponType:     "fixed",
+				Status:         "active",
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponValue:    money.New(10000),
+				FirstOrderOnly: true,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				CustomerID: &newCustomerID,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: true,
+		},
+		{
+			name: "TC1.22: First Order Only for Returning Customer",
+			coupon: model.Coupon{
+				CouponCode:     testString,
+				CouponType:     "fixed",
+				Status:         "active",
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponValue:    money.New(10000),
+				FirstOrderOnly: true,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				CustomerID: &customerID,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
+		{
+			name: "TC1.23: First Order Only without Customer",
+			coupon: model.Coupon{
+				CouponCode:     testString,
+				CouponType:     "fixed",
+				Status:         "active",
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponValue:    money.New(10000),
+				FirstOrderOnly: true,
+			},
+			req: schema.CreateMockOrderRequest{
+				CouponCode: &testString,
+				Cost:       money.New(100000),
+				CreatedAt:  time.Now(),
+			},
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := cs.ValidateCoupon(context.Background(), tt.coupon, tt.req)
-			if err != nil {
-				t.Errorf("ValidateCoupon() error = %v", err)
+			if (err != nil) == tt.want {
+				t.Errorf("ValidateCoupon() error = %v, want valid %v", err, tt.want)
 				return
 			}
 			if got != tt.want {
@@ -97,13 +540,15 @@ func TestValidateCoupon(t *testing.T) {
 
 func TestCalculateAmount(t *testing.T) {
 	logger := logger.New("test")
-	cs := NewCouponService(logger)
+	cs := NewCouponService(logger, CouponServiceDeps{})
 	testString := "TEST123"
+	maxDiscountAmount := money.New(50000)
+	minOrderAmount := money.New(200000)
 	tests := []struct {
 		name    string
 		coupon  *model.Coupon
-		amount  float64
-		want    float64
+		amount  money.Amount
+		want    money.Amount
 		wantErr bool
 	}{
 		{
@@ -115,12 +560,12 @@ func TestCalculateAmount(t *testing.T) {
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(24 * time.Hour),
 				CouponType:  "fixed",
-				CouponValue: 15000,
+				CouponValue: money.New(15000),
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
-			amount: 100000,
-			want:   85000,
+			amount: money.New(100000),
+			want:   money.New(85000),
 		},
 		{
 			name: "TC2.2: Fixed Coupon with Zero ",
@@ -131,12 +576,12 @@ func TestCalculateAmount(t *testing.T) {
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(24 * time.Hour),
 				CouponType:  "fixed",
-				CouponValue: 100000,
+				CouponValue: money.New(100000),
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
-			amount: 100000,
-			want:   0, // Total should not go below zero
+			amount: money.New(100000),
+			want:   money.New(0), // Total should not go below zero
 		},
 		{
 			name: "TC2.3: Fixed Coupon with Negative Amount",
@@ -147,12 +592,12 @@ func TestCalculateAmount(t *testing.T) {
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(24 * time.Hour),
 				CouponType:  "fixed",
-				CouponValue: 15000,
+				CouponValue: money.New(15000),
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
-			amount: 10000, // Amount is less than coupon value
-			want:   0,     // Total should not go below zero
+			amount: money.New(10000), // Amount is less than coupon value
+			want:   money.New(0),     // Total should not go below zero
 		},
 		{
 			name: "TC2.4: Percentage Coupon",
@@ -163,12 +608,12 @@ func TestCalculateAmount(t *testing.T) {
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(24 * time.Hour),
 				CouponType:  "percentage",
-				CouponValue: 20, // 20% discount
+				CouponValue: money.New(20), // 20% discount
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
-			amount: 100000,
-			want:   80000, // 20% off of 100000
+			amount: money.New(100000),
+			want:   money.New(80000), // 20% off of 100000
 		},
 		{
 			name: "TC2.5: Percentage Coupon with Zero",
@@ -179,12 +624,12 @@ func TestCalculateAmount(t *testing.T) {
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(24 * time.Hour),
 				CouponType:  "percentage",
-				CouponValue: 100, // 100% discount
+				CouponValue: money.New(100), // 100% discount
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
-			amount: 100000,
-			want:   0, // Total should not go below zero
+			amount: money.New(100000),
+			want:   money.New(0), // Total should not go below zero
 		},
 		{
 			name: "TC2.6: Percentage Coupon with Negative Amount",
@@ -195,20 +640,80 @@ func TestCalculateAmount(t *testing.T) {
 				Usage:       "lorem ipsum",
 				ExpiredAt:   time.Now().Add(24 * time.Hour),
 				CouponType:  "percentage",
-				CouponValue: 20, // 20% discount
+				CouponValue: money.New(20), // 20% discount
 				CreatedAt:   time.Now(),
 				UpdatedAt:   time.Now(),
 			},
-			amount: 10000, // Amount is less than coupon value
-			want:   8000,  // 20% off of 10000
+			amount: money.New(10000), // Amount is less than coupon value
+			want:   money.New(8000),  // 20% off of 10000
 		},
 		{
 			name:    "TC2.7: No Coupon",
 			coupon:  nil,
-			amount:  100000,
-			want:    100000, // No coupon applied, total should be the same
+			amount:  money.New(100000),
+			want:    money.New(100000), // No coupon applied, total should be the same
 			wantErr: false,
 		},
+		{
+			name: "TC2.8: Percentage Coupon with Max Discount",
+			coupon: &model.Coupon{
+				CouponCode:        testString,
+				ExpiredAt:         time.Now().Add(24 * time.Hour),
+				CouponType:        "percentage",
+				CouponValue:       money.New(20), // 20% discount
+				MaxDiscountAmount: &maxDiscountAmount,
+			},
+			amount: money.New(100000000),
+			want:   money.New(99950000), // 20% off is capped at 50000
+		},
+		{
+			name: "TC2.9: Percentage Coupon under Max Discount",
+			coupon: &model.Coupon{
+				CouponCode:        testString,
+				ExpiredAt:         time.Now().Add(24 * time.Hour),
+				CouponType:        "percentage",
+				CouponValue:       money.New(20), // 20% discount
+				MaxDiscountAmount: &maxDiscountAmount,
+			},
+			amount: money.New(100000),
+			want:   money.New(80000),
+		},
+		{
+			name: "TC2.10: Fixed Coupon below Min Order Amount",
+			coupon: &model.Coupon{
+				CouponCode:     testString,
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponType:     "fixed",
+				CouponValue:    money.New(15000),
+				MinOrderAmount: &minOrderAmount,
+			},
+			amount:  money.New(1000),
+			want:    money.New(1000),
+			wantErr: true,
+		},
+		{
+			name: "TC2.11: Percentage Coupon with Fractional Discount",
+			coupon: &model.Coupon{
+				CouponCode:  testString,
+				ExpiredAt:   time.Now().Add(24 * time.Hour),
+				CouponType:  "percentage",
+				CouponValue: money.New(15), // 15% discount
+			},
+			amount: money.New(99999.99),
+			want:   money.New(84999.99), // 15% of 99999.99 is 14999.9985, rounded to 15000
+		},
+		{
+			name: "TC2.12: Percentage Coupon Rounded to Cents",
+			coupon: &model.Coupon{
+				CouponCode:  testString,
+				ExpiredAt:   time.Now().Add(24 * time.Hour),
+				CouponType:  "percentage",
+				CouponValue: money.New(15), // 15% discount
+				Currency:    "USD",
+			},
+			amount: money.New(99.99),
+			want:   money.New(84.99), // 15% of 99.99 is 14.9985, rounded to 15.00
+		},
 	}
 
 	for _, tt := range tests {
@@ -218,9 +723,602 @@ func TestCalculateAmount(t *testing.T) {
 				t.Errorf("CalculateAmount() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got != tt.want {
+			if !got.Equal(tt.want) {
 				t.Errorf("CalculateAmount() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestValidateStatusTransition(t *testing.T) {
+	logger := logger.New("test")
+	cs := NewCouponService(logger, CouponServiceDeps{})
+	tests := []struct {
+		name    string
+		from    model.CouponStatus
+		to      model.CouponStatus
+		wantErr bool
+	}{
+		{name: "TC3.1: Pause Active Coupon", from: model.CouponStatusActive, to: model.CouponStatusPaused},
+		{name: "TC3.2: Resume Paused Coupon", from: model.CouponStatusPaused, to: model.CouponStatusActive},
+		{name: "TC3.3: Activate Draft Coupon", from: model.CouponStatusDraft, to: model.CouponStatusActive},
+		{name: "TC3.4: Archive Paused Coupon", from: model.CouponStatusPaused, to: model.CouponStatusArchived},
+		{name: "TC3.5: Pause Draft Coupon", from: model.CouponStatusDraft, to: model.CouponStatusPaused, wantErr: true},
+		{name: "TC3.6: Resume Archived Coupon", from: model.CouponStatusArchived, to: model.CouponStatusActive, wantErr: true},
+		{name: "TC3.7: Pause Paused Coupon", from: model.CouponStatusPaused, to: model.CouponStatusPaused, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := model.Coupon{CouponCode: "TEST123", Status: tt.from}
+			err := cs.ValidateStatusTransition(context.Background(), coupon, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStatusTransition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSelectAutoCoupon(t *testing.T) {
+	logger := logger.New("test")
+	cs := NewCouponService(logger, CouponServiceDeps{})
+	minOrderAmount := money.New(500000)
+	newCoupon := func(code string, usage model.CouponUsage, couponType model.CouponType, value float64) model.Coupon {
+		return model.Coupon{
+			CouponCode:  code,
+			CouponType:  couponType,
+			Usage:       usage,
+			Status:      model.CouponStatusActive,
+			ExpiredAt:   time.Now().Add(24 * time.Hour),
+			CouponValue: money.New(value),
+		}
+	}
+	bigSpender := newCoupon("AUTO_BIG", model.CouponUsageAuto, model.CouponTypePercentage, 50)
+	bigSpender.MinOrderAmount = &minOrderAmount
+	tests := []struct {
+		name      string
+		coupons   []model.Coupon
+		cost      money.Amount
+		wantCode  string
+		wantTotal money.Amount
+	}{
+		{
+			name: "TC4.1: Best Auto Coupon Is Applied",
+			coupons: []model.Coupon{
+				newCoupon("AUTO_FIXED", model.CouponUsageAuto, model.CouponTypeFixed, 15000),
+				newCoupon("AUTO_PERCENT", model.CouponUsageAuto, model.CouponTypePercentage, 20),
+			},
+			cost:      money.New(100000),
+			wantCode:  "AUTO_PERCENT",
+			wantTotal: money.New(80000),
+		},
+		{
+			name: "TC4.2: Manual Coupons Are Ignored",
+			coupons: []model.Coupon{
+				newCoupon("MANUAL", model.CouponUsageManual, model.CouponTypeFixed, 50000),
+				newCoupon("AUTO_FIXED", model.CouponUsageAuto, model.CouponTypeFixed, 15000),
+			},
+			cost:      money.New(100000),
+			wantCode:  "AUTO_FIXED",
+			wantTotal: money.New(85000),
+		},
+		{
+			name: "TC4.3: Ineligible Auto Coupons Are Skipped",
+			coupons: []model.Coupon{
+				bigSpender,
+				newCoupon("AUTO_FIXED", model.CouponUsageAuto, model.CouponTypeFixed, 15000),
+			},
+			cost:      money.New(100000),
+			wantCode:  "AUTO_FIXED",
+			wantTotal: money.New(85000),
+		},
+		{
+			name: "TC4.4: No Eligible Auto Coupon",
+			coupons: []model.Coupon{
+				bigSpender,
+			},
+			cost:      money.New(100000),
+			wantCode:  "",
+			wantTotal: money.New(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := schema.CreateMockOrderRequest{Cost: tt.cost, CreatedAt: time.Now()}
+			got, err := cs.SelectAutoCoupon(context.Background(), tt.coupons, req)
+			if err != nil {
+				t.Errorf("SelectAutoCoupon() error = %v", err)
+				return
+			}
+			if tt.wantCode == "" {
+				if got != nil {
+					t.Errorf("SelectAutoCoupon() got = %v, want nil", got.Coupon.CouponCode)
+				}
+				return
+			}
+			if got == nil {
+				t.Errorf("SelectAutoCoupon() got = nil, want %v", tt.wantCode)
+				return
+			}
+			if got.Coupon.CouponCode != tt.wantCode || !got.TotalAmount.Equal(tt.wantTotal) {
+				t.Errorf("SelectAutoCoupon() got = %v (%v), want %v (%v)", got.Coupon.CouponCode, got.TotalAmount, tt.wantCode, tt.wantTotal)
+			}
+			if got.Reason == "" {
+				t.Errorf("SelectAutoCoupon() reason is empty")
+			}
+		})
+	}
+}
+
+func TestRankCoupons(t *testing.T) {
+	logger := logger.New("test")
+	cs := NewCouponService(logger, CouponServiceDeps{})
+	newCoupon := func(code string, couponType model.CouponType, value float64, expiredAt time.Time) model.Coupon {
+		return model.Coupon{
+			CouponCode:  code,
+			CouponType:  couponType,
+			Usage:       model.CouponUsageManual,
+			Status:      model.CouponStatusActive,
+			ExpiredAt:   expiredAt,
+			CouponValue: money.New(value),
+		}
+	}
+	tomorrow := time.Now().Add(24 * time.Hour)
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+	coupons := []model.Coupon{
+		newCoupon("FIXED_15K", model.CouponTypeFixed, 15000, tomorrow),
+		newCoupon("EXPIRED", model.CouponTypeFixed, 90000, time.Now().Add(-time.Hour)),
+		newCoupon("PERCENT_20_LATE", model.CouponTypePercentage, 20, nextWeek),
+		newCoupon("PERCENT_20", model.CouponTypePercentage, 20, tomorrow),
+	}
+	req := schema.CreateMockOrderRequest{Cost: money.New(100000), CreatedAt: time.Now()}
+
+	got := cs.RankCoupons(context.Background(), coupons, req)
+	wantCodes := []string{"PERCENT_20", "PERCENT_20_LATE", "FIXED_15K"}
+	wantTotals := []money.Amount{money.New(80000), money.New(80000), money.New(85000)}
+	if len(got) != len(wantCodes) {
+		t.Fatalf("RankCoupons() got %d coupons, want %d", len(got), len(wantCodes))
+	}
+	for i := range got {
+		if got[i].Coupon.CouponCode != wantCodes[i] || !got[i].TotalAmount.Equal(wantTotals[i]) {
+			t.Errorf("RankCoupons()[%d] got = %v (%v), want %v (%v)", i, got[i].Coupon.CouponCode, got[i].TotalAmount, wantCodes[i], wantTotals[i])
+		}
+	}
+}
+
+func TestApplyCoupons(t *testing.T) {
+	logger := logger.New("test")
+	cs := NewCouponService(logger, CouponServiceDeps{})
+	group := "SITEWIDE"
+	newCoupon := func(code string, couponType model.CouponType, value float64) model.Coupon {
+		return model.Coupon{
+			CouponCode:  code,
+			CouponType:  couponType,
+			Usage:       model.CouponUsageManual,
+			Status:      model.CouponStatusActive,
+			ExpiredAt:   time.Now().Add(24 * time.Hour),
+			CouponValue: money.New(value),
+		}
+	}
+	exclusive := newCoupon("EXCLUSIVE", model.CouponTypeFixed, 10000)
+	exclusive.Exclusive = true
+	groupFixed := newCoupon("GROUP_FIXED", model.CouponTypeFixed, 10000)
+	groupFixed.StackingGroup = &group
+	groupPercent := newCoupon("GROUP_PERCENT", model.CouponTypePercentage, 10)
+	groupPercent.StackingGroup = &group
+	priorityPercent := newCoupon("PRIORITY_PERCENT", model.CouponTypePercentage, 10)
+	priorityPercent.Priority = -1
+	tests := []struct {
+		name      string
+		coupons   []model.Coupon
+		wantCodes []string
+		wantTotal money.Amount
+		wantErr   bool
+	}{
+		{
+			name: "TC5.1: Fixed Applies Before Percentage",
+			coupons: []model.Coupon{
+				newCoupon("PERCENT", model.CouponTypePercentage, 10),
+				newCoupon("FIXED", model.CouponTypeFixed, 20000),
+			},
+			wantCodes: []string{"FIXED", "PERCENT"},
+			wantTotal: money.New(72000), // (100000 - 20000) * 90%
+		},
+		{
+			name: "TC5.2: Priority Overrides Type Order",
+			coupons: []model.Coupon{
+				newCoupon("FIXED", model.CouponTypeFixed, 20000),
+				priorityPercent,
+			},
+			wantCodes: []string{"PRIORITY_PERCENT", "FIXED"},
+			wantTotal: money.New(70000), // 100000 * 90% - 20000
+		},
+		{
+			name: "TC5.3: Exclusive Coupon Cannot Stack",
+			coupons: []model.Coupon{
+				exclusive,
+				newCoupon("FIXED", model.CouponTypeFixed, 20000),
+			},
+			wantErr: true,
+		},
+		{
+			name:      "TC5.4: Exclusive Coupon Alone",
+			coupons:   []model.Coupon{exclusive},
+			wantCodes: []string{"EXCLUSIVE"},
+			wantTotal: money.New(90000),
+		},
+		{
+			name:    "TC5.5: Same Stacking Group Cannot Stack",
+			coupons: []model.Coupon{groupFixed, groupPercent},
+			wantErr: true,
+		},
+		{
+			name: "TC5.6: Duplicate Coupon",
+			coupons: []model.Coupon{
+				newCoupon("FIXED", model.CouponTypeFixed, 20000),
+				newCoupon("FIXED", model.CouponTypeFixed, 20000),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := schema.CreateMockOrderRequest{Cost: money.New(100000), CreatedAt: time.Now()}
+			applied, total, err := cs.ApplyCoupons(context.Background(), tt.coupons, req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyCoupons() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !total.Total().Equal(tt.wantTotal) {
+				t.Errorf("ApplyCoupons() total = %v, want %v", total.Total(), tt.wantTotal)
+			}
+			if len(applied) != len(tt.wantCodes) {
+				t.Fatalf("ApplyCoupons() applied %d coupons, want %d", len(applied), len(tt.wantCodes))
+			}
+			for i := range applied {
+				if applied[i].Coupon.CouponCode != tt.wantCodes[i] {
+					t.Errorf("ApplyCoupons()[%d] = %v, want %v", i, applied[i].Coupon.CouponCode, tt.wantCodes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApplyFreeShippingCoupon(t *testing.T) {
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{Redemptions: fakeRedemptionCounter{}})
+	maxDiscountAmount := money.New(20000)
+	newCoupon := func(code string, couponType model.CouponType, value float64) model.Coupon {
+		return model.Coupon{
+			CouponCode:  code,
+			CouponType:  couponType,
+			Status:      model.CouponStatusActive,
+			ExpiredAt:   time.Now().Add(24 * time.Hour),
+			CouponValue: money.New(value),
+		}
+	}
+	cappedShipping := newCoupon("CAPPED_SHIPPING", model.CouponTypeFreeShipping, 0)
+	cappedShipping.MaxDiscountAmount = &maxDiscountAmount
+	tests := []struct {
+		name            string
+		coupons         []model.Coupon
+		shippingFee     money.Amount
+		wantSubtotal    money.Amount
+		wantShippingFee money.Amount
+		wantErr         bool
+	}{
+		{
+			name:            "TC6.1: Free Shipping Waives Shipping Only",
+			coupons:         []model.Coupon{newCoupon("FREE_SHIPPING", model.CouponTypeFreeShipping, 0)},
+			shippingFee:     money.New(30000),
+			wantSubtotal:    money.New(100000),
+			wantShippingFee: money.New(0),
+		},
+		{
+			name:            "TC6.2: Free Shipping Capped by Max Discount",
+			coupons:         []model.Coupon{cappedShipping},
+			shippingFee:     money.New(30000),
+			wantSubtotal:    money.New(100000),
+			wantShippingFee: money.New(10000),
+		},
+		{
+			name: "TC6.3: Percentage Coupon Does Not Discount Shipping",
+			coupons: []model.Coupon{
+				newCoupon("PERCENT", model.CouponTypePercentage, 10),
+				newCoupon("FREE_SHIPPING", model.CouponTypeFreeShipping, 0),
+			},
+			shippingFee:     money.New(30000),
+			wantSubtotal:    money.New(90000),
+			wantShippingFee: money.New(0),
+		},
+		{
+			name:        "TC6.4: Free Shipping Without Shipping Fee",
+			coupons:     []model.Coupon{newCoupon("FREE_SHIPPING", model.CouponTypeFreeShipping, 0)},
+			shippingFee: money.New(0),
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := schema.CreateMockOrderRequest{Cost: money.New(100000), ShippingFee: tt.shippingFee, CreatedAt: time.Now()}
+			_, amounts, err := cs.ApplyCoupons(context.Background(), tt.coupons, req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyCoupons() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !amounts.Subtotal.Equal(tt.wantSubtotal) || !amounts.ShippingFee.Equal(tt.wantShippingFee) {
+				t.Errorf("ApplyCoupons() got = %v + %v, want %v + %v", amounts.Subtotal, amounts.ShippingFee, tt.wantSubtotal, tt.wantShippingFee)
+			}
+		})
+	}
+}
+
+func TestValidateCouponWindows(t *testing.T) {
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{})
+	saigon := "Asia/Ho_Chi_Minh"
+	happyHour := model.CouponWindows{{Weekdays: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "17:00", End: "19:00"}}
+	lateNight := model.CouponWindows{{Weekdays: []string{"fri", "sat"}, Start: "22:00", End: "02:00"}}
+	tests := []struct {
+		name     string
+		windows  model.CouponWindows
+		timeZone *string
+		at       time.Time
+		want     bool
+	}{
+		{name: "TC11.1: Inside Weekday Window", windows: happyHour, timeZone: &saigon, at: time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC), want: true},
+		{name: "TC11.2: Before Weekday Window", windows: happyHour, timeZone: &saigon, at: time.Date(2026, 10, 19, 8, 30, 0, 0, time.UTC), want: false},
+		{name: "TC11.3: End of Window Is Exclusive", windows: happyHour, timeZone: &saigon, at: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), want: false},
+		{name: "TC11.4: Weekend outside Weekday Window", windows: happyHour, timeZone: &saigon, at: time.Date(2026, 10, 24, 10, 30, 0, 0, time.UTC), want: false},
+		{name: "TC11.5: Window Read in UTC without Time Zone", windows: happyHour, at: time.Date(2026, 10, 19, 10, 30, 0, 0, time.UTC), want: false},
+		{name: "TC11.6: Overnight Window after Midnight", windows: lateNight, timeZone: &saigon, at: time.Date(2026, 10, 23, 18, 0, 0, 0, time.UTC), want: true},
+		{name: "TC11.7: Overnight Window Started Saturday", windows: lateNight, timeZone: &saigon, at: time.Date(2026, 10, 24, 18, 0, 0, 0, time.UTC), want: true},
+		{name: "TC11.8: Overnight Window Not Started Sunday", windows: lateNight, timeZone: &saigon, at: time.Date(2026, 10, 25, 18, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := model.Coupon{
+				CouponCode:      "HAPPY_HOUR",
+				CouponType:      model.CouponTypeFixed,
+				Status:          model.CouponStatusActive,
+				ExpiredAt:       tt.at.Add(24 * time.Hour),
+				CouponValue:     money.New(10000),
+				ValidityWindows: tt.windows,
+				TimeZone:        tt.timeZone,
+			}
+			req := schema.CreateMockOrderRequest{Cost: money.New(100000), CreatedAt: tt.at}
+			got, err := cs.ValidateCoupon(context.Background(), coupon, req)
+			if got != tt.want {
+				t.Errorf("ValidateCoupon() got = %v, want %v (error: %v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateCouponChannel(t *testing.T) {
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{})
+	web, app, momo, card := "web", "app", "momo", "card"
+	tests := []struct {
+		name          string
+		channels      model.StringList
+		methods       model.StringList
+		channel       *string
+		paymentMethod *string
+		want          bool
+	}{
+		{name: "TC12.1: Unrestricted Coupon", channel: &web, want: true},
+		{name: "TC12.2: Allowed Channel", channels: model.StringList{"web", "app"}, channel: &app, want: true},
+		{name: "TC12.3: Other Channel", channels: model.StringList{"web"}, channel: &app, want: false},
+		{name: "TC12.4: Order without Channel", channels: model.StringList{"web"}, want: false},
+		{name: "TC12.5: Allowed Payment Method", methods: model.StringList{"momo"}, paymentMethod: &momo, want: true},
+		{name: "TC12.6: Other Payment Method", methods: model.StringList{"momo"}, paymentMethod: &card, want: false},
+		{name: "TC12.7: Order without Payment Method", methods: model.StringList{"momo"}, channel: &web, want: false},
+		{name: "TC12.8: Both Restrictions Met", channels: model.StringList{"app"}, methods: model.StringList{"momo"}, channel: &app, paymentMethod: &momo, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := model.Coupon{
+				CouponCode:     "WALLET",
+				CouponType:     model.CouponTypeFixed,
+				Status:         model.CouponStatusActive,
+				ExpiredAt:      time.Now().Add(24 * time.Hour),
+				CouponValue:    money.New(10000),
+				Channels:       tt.channels,
+				PaymentMethods: tt.methods,
+			}
+			req := schema.CreateMockOrderRequest{
+				Cost:          money.New(100000),
+				Channel:       tt.channel,
+				PaymentMethod: tt.paymentMethod,
+				CreatedAt:     time.Now(),
+			}
+			got, err := cs.ValidateCoupon(context.Background(), coupon, req)
+			if got != tt.want {
+				t.Errorf("ValidateCoupon() got = %v, want %v (error: %v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCouponBudget(t *testing.T) {
+	now := time.Now()
+	campaignBudget := money.New(100000)
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{
+		Campaigns: fakeCampaigns{
+			1: {ID: 1, Name: "Capped", Budget: &campaignBudget, StartsAt: now.Add(-24 * time.Hour), EndsAt: now.Add(24 * time.Hour)},
+		},
+		Ledger: fakeDiscountLedger{
+			coupons:   map[string]money.Amount{"BUDGET": money.New(80000)},
+			campaigns: map[uint64]money.Amount{1: money.New(85000)},
+		},
+	})
+	campaignID := uint64(1)
+	large, exact, short, spent := money.New(200000), money.New(110000), money.New(100000), money.New(80000)
+	parentCode := "BUDGET"
+	tests := []struct {
+		name         string
+		parent       *string
+		budget       *money.Amount
+		campaignID   *uint64
+		partial      bool
+		want         bool
+		wantDiscount money.Amount
+	}{
+		{name: "TC14.1: Coupon without Budget", want: true, wantDiscount: money.New(30000)},
+		{name: "TC14.2: Budget Covers Discount", budget: &large, want: true, wantDiscount: money.New(30000)},
+		{name: "TC14.3: Budget Exactly Covers Discount", budget: &exact, want: true, wantDiscount: money.New(30000)},
+		{name: "TC14.4: Discount Exceeds Budget", budget: &short, want: false},
+		{name: "TC14.5: Partial Discount from Budget", budget: &short, partial: true, want: true, wantDiscount: money.New(20000)},
+		{name: "TC14.6: Budget Used Up", budget: &spent, partial: true, want: false},
+		{name: "TC14.7: Discount Exceeds Campaign Budget", campaignID: &campaignID, want: false},
+		{name: "TC14.8: Partial Discount from Campaign Budget", budget: &large, campaignID: &campaignID, partial: true, want: true, wantDiscount: money.New(15000)},
+		{name: "TC14.9: Batch Code Exceeds Parent Budget", parent: &parentCode, budget: &short, want: false},
+		{name: "TC14.10: Batch Code within Parent Budget", parent: &parentCode, budget: &exact, want: true, wantDiscount: money.New(30000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := "BUDGET"
+			if tt.parent != nil {
+				code = "BUDGETX1"
+			}
+			coupon := model.Coupon{
+				CouponCode:           code,
+				ParentCouponCode:     tt.parent,
+				CouponType:           model.CouponTypeFixed,
+				Status:               model.CouponStatusActive,
+				ExpiredAt:            now.Add(72 * time.Hour),
+				CouponValue:          money.New(30000),
+				Budget:               tt.budget,
+				CampaignID:           tt.campaignID,
+				AllowPartialDiscount: tt.partial,
+			}
+			req := schema.CreateMockOrderRequest{
+				Cost:      money.New(100000),
+				CreatedAt: now,
+			}
+			applied, _, err := cs.ApplyCoupons(context.Background(), []model.Coupon{coupon}, req)
+			if got := err == nil; got != tt.want {
+				t.Fatalf("ApplyCoupons() got = %v, want %v (error: %v)", got, tt.want, err)
+			}
+			if err != nil {
+				return
+			}
+			if !applied[0].DiscountAmount.Equal(tt.wantDiscount) {
+				t.Errorf("ApplyCoupons() discount = %s, want %s", applied[0].DiscountAmount, tt.wantDiscount)
+			}
+			if applied[0].Coupon.MaxDiscountAmount != nil {
+				t.Errorf("ApplyCoupons() changed the coupon's max_discount_amount to %s", applied[0].Coupon.MaxDiscountAmount)
+			}
+		})
+	}
+}
+
+func TestValidateCouponWallet(t *testing.T) {
+	now := time.Now()
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{Wallets: fakeWallets{
+		"HOLDER":  {{CustomerID: "HOLDER", CouponCode: "CLAIMED", ClaimedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)}},
+		"EXPIRED": {{CustomerID: "EXPIRED", CouponCode: "CLAIMED", ClaimedAt: now.Add(-48 * time.Hour), ExpiresAt: now.Add(-time.Hour)}},
+	}})
+	holder, expired, stranger := "HOLDER", "EXPIRED", "STRANGER"
+	tests := []struct {
+		name          string
+		requiresClaim bool
+		customerID    *string
+		want          bool
+	}{
+		{name: "TC15.1: Coupon without Claim", requiresClaim: false, customerID: &stranger, want: true},
+		{name: "TC15.2: Claimed Coupon", requiresClaim: true, customerID: &holder, want: true},
+		{name: "TC15.3: Unclaimed Coupon", requiresClaim: true, customerID: &stranger, want: false},
+		{name: "TC15.4: Expired Claim", requiresClaim: true, customerID: &expired, want: false},
+		{name: "TC15.5: Order without Customer", requiresClaim: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coupon := model.Coupon{
+				CouponCode:    "CLAIMED",
+				CouponType:    model.CouponTypeFixed,
+				Status:        model.CouponStatusActive,
+				ExpiredAt:     now.Add(72 * time.Hour),
+				CouponValue:   money.New(10000),
+				RequiresClaim: tt.requiresClaim,
+			}
+			req := schema.CreateMockOrderRequest{
+				CustomerID: tt.customerID,
+				Cost:       money.New(100000),
+				CreatedAt:  now,
+			}
+			got, err := cs.ValidateCoupon(context.Background(), coupon, req)
+			if got != tt.want {
+				t.Errorf("ValidateCoupon() got = %v, want %v (error: %v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateClaim(t *testing.T) {
+	now := time.Now()
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{Segments: fakeCustomerSegments{"vip": {"VIP"}}})
+	vip := "vip"
+	later := now.Add(time.Hour)
+	tests := []struct {
+		name       string
+		coupon     model.Coupon
+		customerID string
+		wantErr    bool
+	}{
+		{
+			name:       "TC15.6: Active Coupon",
+			coupon:     model.Coupon{Status: model.CouponStatusActive, ExpiredAt: now.Add(time.Hour)},
+			customerID: "CUSTOMER",
+		},
+		{
+			name:       "TC15.7: Coupon not Started Yet",
+			coupon:     model.Coupon{Status: model.CouponStatusActive, StartsAt: &later, ExpiredAt: now.Add(2 * time.Hour)},
+			customerID: "CUSTOMER",
+		},
+		{
+			name:       "TC15.8: Expired Coupon",
+			coupon:     model.Coupon{Status: model.CouponStatusActive, ExpiredAt: now.Add(-time.Hour)},
+			customerID: "CUSTOMER",
+			wantErr:    true,
+		},
+		{
+			name:       "TC15.9: Paused Coupon",
+			coupon:     model.Coupon{Status: model.CouponStatusPaused, ExpiredAt: now.Add(time.Hour)},
+			customerID: "CUSTOMER",
+			wantErr:    true,
+		},
+		{
+			name:       "TC15.10: Customer outside Segment",
+			coupon:     model.Coupon{Status: model.CouponStatusActive, ExpiredAt: now.Add(time.Hour), CustomerSegment: &vip},
+			customerID: "CUSTOMER",
+			wantErr:    true,
+		},
+		{
+			name:       "TC15.11: Customer in Segment",
+			coupon:     model.Coupon{Status: model.CouponStatusActive, ExpiredAt: now.Add(time.Hour), CustomerSegment: &vip},
+			customerID: "VIP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.coupon.CouponCode = "CLAIM"
+			err := cs.ValidateClaim(context.Background(), tt.coupon, tt.customerID, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateClaim() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
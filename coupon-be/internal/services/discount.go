@@ -0,0 +1,213 @@
+package services
+
+import (
+	"coupon-be/internal/model"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/money"
+	"fmt"
+	"sort"
+)
+
+// DiscountCalculator implements one coupon type: it checks the parameters a
+// coupon of that type is created with and takes its discount off an order.
+type DiscountCalculator interface {
+	// Validate rejects coupons whose parameters make no sense for the type.
+	Validate(coupon model.Coupon) error
+	// CheckOrder rejects orders the coupon has nothing to discount on.
+	CheckOrder(coupon model.Coupon, req schema.CreateMockOrderRequest) error
+	// Apply returns the order amounts left after the coupon's discount. It
+	// does not check whether the order qualifies for the coupon.
+	Apply(coupon model.Coupon, amounts OrderAmounts) (OrderAmounts, error)
+}
+
+// TieredCalculator is implemented by calculators that take the discount of
+// one of the coupon's tiers.
+type TieredCalculator interface {
+	// SelectTier returns the index of the tier Apply takes the discount of,
+	// or -1 if the amounts reach no tier.
+	SelectTier(coupon model.Coupon, amounts OrderAmounts) int
+}
+
+// DiscountRegistry maps coupon types to their calculators.
+type DiscountRegistry struct {
+	calculators map[model.CouponType]DiscountCalculator
+}
+
+func NewDiscountRegistry() *DiscountRegistry {
+	return &DiscountRegistry{calculators: make(map[model.CouponType]DiscountCalculator)}
+}
+
+// DefaultDiscountRegistry returns a registry with every built-in coupon type.
+func DefaultDiscountRegistry() *DiscountRegistry {
+	r := NewDiscountRegistry()
+	r.Register(model.CouponTypeFixed, fixedDiscount{})
+	r.Register(model.CouponTypePercentage, percentageDiscount{})
+	r.Register(model.CouponTypeFreeShipping, freeShippingDiscount{})
+	r.Register(model.CouponTypeTiered, tieredDiscount{})
+	r.Register(model.CouponTypeBuyXGetY, buyXGetYDiscount{})
+	return r
+}
+
+// Register adds or replaces the calculator of a coupon type.
+func (r *DiscountRegistry) Register(couponType model.CouponType, calculator DiscountCalculator) {
+	r.calculators[couponType] = calculator
+}
+
+// Get returns the calculator of a coupon type.
+func (r *DiscountRegistry) Get(couponType model.CouponType) (DiscountCalculator, error) {
+	calculator, ok := r.calculators[couponType]
+	if !ok {
+		return nil, fmt.Errorf("invalid coupon type: %s", couponType)
+	}
+	return calculator, nil
+}
+
+// Types returns the registered coupon types in alphabetical order.
+func (r *DiscountRegistry) Types() []model.CouponType {
+	types := make([]model.CouponType, 0, len(r.calculators))
+	for couponType := range r.calculators {
+		types = append(types, couponType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// fixedDiscount takes CouponValue off the subtotal.
+type fixedDiscount struct{}
+
+func (fixedDiscount) Validate(coupon model.Coupon) error {
+	if !coupon.CouponValue.GreaterThan(money.Zero) {
+		return fmt.Errorf("coupon_value must be greater than 0 for %s coupons", coupon.CouponType)
+	}
+	if !coupon.CouponValue.FitsMinorUnit(coupon.Currency) {
+		return fmt.Errorf("coupon_value %s has more decimals than %s allows", coupon.CouponValue, coupon.Currency.OrDefault())
+	}
+	return nil
+}
+
+func (fixedDiscount) CheckOrder(coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	return nil
+}
+
+func (fixedDiscount) Apply(coupon model.Coupon, amounts OrderAmounts) (OrderAmounts, error) {
+	discountedAmount := amounts.Subtotal.Sub(coupon.CouponValue)
+	if discountedAmount.IsNegative() {
+		discountedAmount = money.Zero // Ensure the total does not go below zero
+	}
+	amounts.Subtotal = capDiscount(coupon, amounts.Subtotal, discountedAmount)
+	return amounts, nil
+}
+
+// percentageDiscount takes CouponValue percent off the subtotal.
+type percentageDiscount struct{}
+
+func (percentageDiscount) Validate(coupon model.Coupon) error {
+	if !coupon.CouponValue.GreaterThan(money.Zero) || coupon.CouponValue.GreaterThan(money.NewFromInt(100)) {
+		return fmt.Errorf("coupon_value must be between 0 and 100 for %s coupons", coupon.CouponType)
+	}
+	return nil
+}
+
+func (percentageDiscount) CheckOrder(coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	return nil
+}
+
+func (percentageDiscount) Apply(coupon model.Coupon, amounts OrderAmounts) (OrderAmounts, error) {
+	amounts.Subtotal = percentOff(coupon, amounts.Subtotal, coupon.CouponValue)
+	return amounts, nil
+}
+
+// freeShippingDiscount waives the shipping fee, up to MaxDiscountAmount.
+type freeShippingDiscount struct{}
+
+func (freeShippingDiscount) Validate(coupon model.Coupon) error {
+	return nil
+}
+
+func (freeShippingDiscount) CheckOrder(coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	if !req.ShippingFee.GreaterThan(money.Zero) {
+		return fmt.Errorf("coupon %s only discounts shipping and the order has no shipping fee", coupon.CouponCode)
+	}
+	return nil
+}
+
+func (freeShippingDiscount) Apply(coupon model.Coupon, amounts OrderAmounts) (OrderAmounts, error) {
+	amounts.ShippingFee = capDiscount(coupon, amounts.ShippingFee, money.Zero)
+	return amounts, nil
+}
+
+// tieredDiscount takes the discount of the highest tier the subtotal reaches.
+type tieredDiscount struct{}
+
+func (tieredDiscount) Validate(coupon model.Coupon) error {
+	if len(coupon.Tiers) == 0 {
+		return fmt.Errorf("tiers are required for %s coupons", coupon.CouponType)
+	}
+	for i, tier := range coupon.Tiers {
+		if tier.Threshold.IsNegative() {
+			return fmt.Errorf("tier %d: threshold must not be negative", i+1)
+		}
+		if !tier.Threshold.FitsMinorUnit(coupon.Currency) {
+			return fmt.Errorf("tier %d: threshold %s has more decimals than %s allows", i+1, tier.Threshold, coupon.Currency.OrDefault())
+		}
+		if !tier.Discount.GreaterThan(money.Zero) || tier.Discount.GreaterThan(money.NewFromInt(100)) {
+			return fmt.Errorf("tier %d: discount must be between 0 and 100", i+1)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := coupon.Tiers[i-1]
+		if !tier.Threshold.GreaterThan(previous.Threshold) {
+			return fmt.Errorf("tier %d: threshold must be greater than the threshold of tier %d", i+1, i)
+		}
+		if !tier.Discount.GreaterThan(previous.Discount) {
+			return fmt.Errorf("tier %d: discount must be greater than the discount of tier %d", i+1, i)
+		}
+	}
+	return nil
+}
+
+func (tieredDiscount) CheckOrder(coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	amount := orderAmounts(req).targeted(coupon).Subtotal
+	if len(coupon.Tiers) > 0 && coupon.Tiers.Select(amount) < 0 {
+		return fmt.Errorf("order amount %s does not reach the lowest tier of coupon %s: a minimum order of %s is required", amount.StringFixed(), coupon.CouponCode, coupon.Tiers[0].Threshold.StringFixed())
+	}
+	return nil
+}
+
+func (tieredDiscount) SelectTier(coupon model.Coupon, amounts OrderAmounts) int {
+	return coupon.Tiers.Select(amounts.Subtotal)
+}
+
+func (d tieredDiscount) Apply(coupon model.Coupon, amounts OrderAmounts) (OrderAmounts, error) {
+	tier := d.SelectTier(coupon, amounts)
+	if tier < 0 {
+		return amounts, nil
+	}
+	amounts.Subtotal = percentOff(coupon, amounts.Subtotal, coupon.Tiers[tier].Discount)
+	return amounts, nil
+}
+
+// percentOff takes percent % off amount. The discount is rounded to the minor
+// unit of the coupon's currency before it is taken off, so the discount and
+// the total always add up to the original amount.
+func percentOff(coupon model.Coupon, amount, percent money.Amount) money.Amount {
+	discount := amount.Percent(percent).RoundFor(coupon.Currency)
+	discountedAmount := amount.Sub(discount)
+	if discountedAmount.IsNegative() {
+		discountedAmount = money.Zero // Ensure the total does not go below zero
+	}
+	return capDiscount(coupon, amount, discountedAmount)
+}
+
+// capDiscount limits the discount taken off amount to the coupon's
+// MaxDiscountAmount, if any.
+func capDiscount(coupon model.Coupon, amount, discountedAmount money.Amount) money.Amount {
+	if coupon.MaxDiscountAmount == nil {
+		return discountedAmount
+	}
+	if amount.Sub(discountedAmount).GreaterThan(*coupon.MaxDiscountAmount) {
+		return amount.Sub(*coupon.MaxDiscountAmount)
+	}
+	return discountedAmount
+}
@@ -0,0 +1,482 @@
+package services
+
+import (
+	"context"
+	"coupon-be/internal/model"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/logger"
+	"coupon-be/pkg/money"
+	"testing"
+	"time"
+)
+
+// halfShippingDiscount is a coupon type registered by a test to show new
+// types need no change to the service.
+type halfShippingDiscount struct{}
+
+func (halfShippingDiscount) Validate(coupon model.Coupon) error {
+	return nil
+}
+
+func (halfShippingDiscount) CheckOrder(coupon model.Coupon, req schema.CreateMockOrderRequest) error {
+	return nil
+}
+
+func (halfShippingDiscount) Apply(coupon model.Coupon, amounts OrderAmounts) (OrderAmounts, error) {
+	amounts.ShippingFee = amounts.ShippingFee.Percent(money.New(50)).Round()
+	return amounts, nil
+}
+
+func TestValidateCouponParams(t *testing.T) {
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{})
+	tests := []struct {
+		name    string
+		coupon  model.Coupon
+		wantErr bool
+	}{
+		{
+			name:   "TC7.1: Fixed Coupon with Value",
+			coupon: model.Coupon{CouponType: model.CouponTypeFixed, CouponValue: money.New(15000)},
+		},
+		{
+			name:    "TC7.2: Fixed Coupon without Value",
+			coupon:  model.Coupon{CouponType: model.CouponTypeFixed},
+			wantErr: true,
+		},
+		{
+			name:    "TC7.3: Percentage Coupon above 100",
+			coupon:  model.Coupon{CouponType: model.CouponTypePercentage, CouponValue: money.New(120)},
+			wantErr: true,
+		},
+		{
+			name:   "TC7.4: Free Shipping Coupon without Value",
+			coupon: model.Coupon{CouponType: model.CouponTypeFreeShipping},
+		},
+		{
+			name:    "TC7.5: Unknown Coupon Type",
+			coupon:  model.Coupon{CouponType: "lottery", CouponValue: money.New(10)},
+			wantErr: true,
+		},
+		{
+			name: "TC7.6: Tiered Coupon with Increasing Tiers",
+			coupon: model.Coupon{CouponType: model.CouponTypeTiered, Tiers: model.CouponTiers{
+				{Threshold: money.New(200000), Discount: money.New(10)},
+				{Threshold: money.New(500000), Discount: money.New(15)},
+			}},
+		},
+		{
+			name:    "TC7.7: Tiered Coupon without Tiers",
+			coupon:  model.Coupon{CouponType: model.CouponTypeTiered},
+			wantErr: true,
+		},
+		{
+			name: "TC7.8: Tiered Coupon with Decreasing Threshold",
+			coupon: model.Coupon{CouponType: model.CouponTypeTiered, Tiers: model.CouponTiers{
+				{Threshold: money.New(500000), Discount: money.New(10)},
+				{Threshold: money.New(200000), Discount: money.New(15)},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "TC7.9: Tiered Coupon with Decreasing Discount",
+			coupon: model.Coupon{CouponType: model.CouponTypeTiered, Tiers: model.CouponTiers{
+				{Threshold: money.New(200000), Discount: money.New(15)},
+				{Threshold: money.New(500000), Discount: money.New(10)},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "TC7.10: Buy X Get Y Coupon with Rules",
+			coupon: model.Coupon{CouponType: model.CouponTypeBuyXGetY, BuyXGetY: &model.CouponBuyXGetY{
+				Buy: model.CouponItemRule{SKUs: []string{"A"}, Quantity: 2},
+				Get: model.CouponItemRule{SKUs: []string{"B"}, Quantity: 1},
+			}},
+		},
+		{
+			name:    "TC7.11: Buy X Get Y Coupon without Rules",
+			coupon:  model.Coupon{CouponType: model.CouponTypeBuyXGetY},
+			wantErr: true,
+		},
+		{
+			name: "TC7.12: Buy X Get Y Coupon without Reward Items",
+			coupon: model.Coupon{CouponType: model.CouponTypeBuyXGetY, BuyXGetY: &model.CouponBuyXGetY{
+				Buy: model.CouponItemRule{SKUs: []string{"A"}, Quantity: 2},
+				Get: model.CouponItemRule{Quantity: 1},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "TC7.13: Validity Window with Unknown Weekday",
+			coupon: model.Coupon{CouponType: model.CouponTypeFixed, CouponValue: money.New(15000), ValidityWindows: model.CouponWindows{
+				{Weekdays: []string{"someday"}, Start: "17:00", End: "19:00"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "TC7.14: Validity Window with Malformed Time",
+			coupon: model.Coupon{CouponType: model.CouponTypeFixed, CouponValue: money.New(15000), ValidityWindows: model.CouponWindows{
+				{Start: "5pm", End: "19:00"},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "TC7.15: Unknown Time Zone",
+			coupon:  model.Coupon{CouponType: model.CouponTypeFixed, CouponValue: money.New(15000), TimeZone: func() *string { s := "Mars/Olympus"; return &s }()},
+			wantErr: true,
+		},
+		{
+			name:    "TC7.16: Claim Limit on Claimed Coupon",
+			coupon:  model.Coupon{CouponType: model.CouponTypeFixed, CouponValue: money.New(15000), RequiresClaim: true, ClaimLimit: func() *int { n := 300; return &n }()},
+			wantErr: false,
+		},
+		{
+			name:    "TC7.17: Claim Limit without Claiming",
+			coupon:  model.Coupon{CouponType: model.CouponTypeFixed, CouponValue: money.New(15000), ClaimLimit: func() *int { n := 300; return &n }()},
+			wantErr: true,
+		},
+		{
+			name:    "TC7.18: Fixed VND Coupon with Fractional Value",
+			coupon:  model.Coupon{CouponType: model.CouponTypeFixed, CouponValue: money.New(100.55), Currency: "VND"},
+			wantErr: true,
+		},
+		{
+			name:   "TC7.19: Fixed USD Coupon with Cents",
+			coupon: model.Coupon{CouponType: model.CouponTypeFixed, CouponValue: money.New(100.55), Currency: "USD"},
+		},
+		{
+			name:   "TC7.20: Percentage VND Coupon with Fractional Percent",
+			coupon: model.Coupon{CouponType: model.CouponTypePercentage, CouponValue: money.New(12.5), Currency: "VND"},
+		},
+		{
+			name:    "TC7.21: VND Coupon with Fractional Minimum Order",
+			coupon:  model.Coupon{CouponType: model.CouponTypePercentage, CouponValue: money.New(10), MinOrderAmount: func() *money.Amount { a := money.New(99999.5); return &a }()},
+			wantErr: true,
+		},
+		{
+			name: "TC7.22: Tiered VND Coupon with Fractional Threshold",
+			coupon: model.Coupon{CouponType: model.CouponTypeTiered, Tiers: model.CouponTiers{
+				{Threshold: money.New(200000.25), Discount: money.New(10)},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := cs.ValidateCouponParams(context.Background(), tt.coupon)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCouponParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterDiscountCalculator(t *testing.T) {
+	registry := DefaultDiscountRegistry()
+	registry.Register("half_shipping", halfShippingDiscount{})
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{Discounts: registry})
+
+	coupon := model.Coupon{
+		CouponCode: "HALF_SHIPPING",
+		CouponType: "half_shipping",
+		Status:     model.CouponStatusActive,
+		ExpiredAt:  time.Now().Add(24 * time.Hour),
+	}
+	req := schema.CreateMockOrderRequest{Cost: money.New(100000), ShippingFee: money.New(30000), CreatedAt: time.Now()}
+	_, amounts, err := cs.ApplyCoupons(context.Background(), []model.Coupon{coupon}, req)
+	if err != nil {
+		t.Fatalf("ApplyCoupons() error = %v", err)
+	}
+	if !amounts.Total().Equal(money.New(115000)) {
+		t.Errorf("ApplyCoupons() total = %v, want 115000", amounts.Total())
+	}
+}
+
+func TestApplyTieredCoupon(t *testing.T) {
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{})
+	coupon := model.Coupon{
+		CouponCode: "TIERED",
+		CouponType: model.CouponTypeTiered,
+		Status:     model.CouponStatusActive,
+		ExpiredAt:  time.Now().Add(24 * time.Hour),
+		Tiers: model.CouponTiers{
+			{Threshold: money.New(200000), Discount: money.New(10)},
+			{Threshold: money.New(500000), Discount: money.New(15)},
+		},
+	}
+	tests := []struct {
+		name      string
+		cost      money.Amount
+		wantTotal money.Amount
+		wantTier  int
+		wantErr   bool
+	}{
+		{name: "TC8.1: Below Lowest Tier", cost: money.New(150000), wantErr: true},
+		{name: "TC8.2: Lowest Tier", cost: money.New(200000), wantTotal: money.New(180000), wantTier: 1},
+		{name: "TC8.3: Between Tiers", cost: money.New(400000), wantTotal: money.New(360000), wantTier: 1},
+		{name: "TC8.4: Highest Tier", cost: money.New(600000), wantTotal: money.New(510000), wantTier: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := schema.CreateMockOrderRequest{Cost: tt.cost, CreatedAt: time.Now()}
+			applied, amounts, err := cs.ApplyCoupons(context.Background(), []model.Coupon{coupon}, req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyCoupons() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !amounts.Total().Equal(tt.wantTotal) || applied[0].Tier != tt.wantTier {
+				t.Errorf("ApplyCoupons() got = %v (tier %d), want %v (tier %d)", amounts.Total(), applied[0].Tier, tt.wantTotal, tt.wantTier)
+			}
+		})
+	}
+}
+
+func TestTargetedTieredCouponTier(t *testing.T) {
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{})
+	coupon := model.Coupon{
+		CouponCode: "TIERED",
+		CouponType: model.CouponTypeTiered,
+		Status:     model.CouponStatusActive,
+		ExpiredAt:  time.Now().Add(24 * time.Hour),
+		Tiers: model.CouponTiers{
+			{Threshold: money.New(200000), Discount: money.New(10)},
+			{Threshold: money.New(500000), Discount: money.New(15)},
+		},
+		Targeting: &model.CouponTargeting{IncludeCategories: []string{"shoes"}},
+	}
+	// The order reaches the second tier, but only the shoes it targets count.
+	req := schema.CreateMockOrderRequest{
+		Cost: money.New(600000),
+		Items: []schema.OrderItemRequest{
+			{SKU: "SHOE-1", Category: "shoes", Quantity: 1, UnitPrice: money.New(300000)},
+			{SKU: "SHIRT-1", Category: "shirts", Quantity: 1, UnitPrice: money.New(300000)},
+		},
+		CreatedAt: time.Now(),
+	}
+
+	applied, amounts, err := cs.ApplyCoupons(context.Background(), []model.Coupon{coupon}, req)
+	if err != nil {
+		t.Fatalf("ApplyCoupons() error = %v", err)
+	}
+	if !amounts.Total().Equal(money.New(570000)) || applied[0].Tier != 1 {
+		t.Errorf("ApplyCoupons() got = %v (tier %d), want 570000 (tier 1)", amounts.Total(), applied[0].Tier)
+	}
+	ranked := cs.RankCoupons(context.Background(), []model.Coupon{coupon}, req)
+	if len(ranked) != 1 || ranked[0].Tier != 1 {
+		t.Errorf("RankCoupons() got = %+v, want tier 1", ranked)
+	}
+}
+
+func TestApplyTargetedCoupon(t *testing.T) {
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{})
+	newCoupon := func(couponType model.CouponType, value float64, targeting model.CouponTargeting) model.Coupon {
+		return model.Coupon{
+			CouponCode:  "TARGETED",
+			CouponType:  couponType,
+			Status:      model.CouponStatusActive,
+			ExpiredAt:   time.Now().Add(24 * time.Hour),
+			CouponValue: money.New(value),
+			Targeting:   &targeting,
+		}
+	}
+	items := []schema.OrderItemRequest{
+		{SKU: "SHOE-1", Category: "shoes", Quantity: 2, UnitPrice: money.New(100000)},
+		{SKU: "SHOE-2", Category: "shoes", Quantity: 1, UnitPrice: money.New(50000)},
+		{SKU: "SHIRT-1", Category: "shirts", Quantity: 1, UnitPrice: money.New(80000)},
+	}
+	tests := []struct {
+		name           string
+		coupon         model.Coupon
+		items          []schema.OrderItemRequest
+		wantLineTotals []money.Amount
+		wantErr        bool
+	}{
+		{
+			name:           "TC9.1: Percentage Coupon on One Category",
+			coupon:         newCoupon(model.CouponTypePercentage, 10, model.CouponTargeting{IncludeCategories: []string{"shoes"}}),
+			items:          items,
+			wantLineTotals: []money.Amount{money.New(180000), money.New(45000), money.New(80000)},
+		},
+		{
+			name:           "TC9.2: Fixed Coupon Spread over Eligible Lines",
+			coupon:         newCoupon(model.CouponTypeFixed, 30000, model.CouponTargeting{IncludeCategories: []string{"shoes"}}),
+			items:          items,
+			wantLineTotals: []money.Amount{money.New(176000), money.New(44000), money.New(80000)},
+		},
+		{
+			name:           "TC9.3: Excluded SKU Is Not Discounted",
+			coupon:         newCoupon(model.CouponTypePercentage, 10, model.CouponTargeting{ExcludeSKUs: []string{"SHOE-1"}}),
+			items:          items,
+			wantLineTotals: []money.Amount{money.New(200000), money.New(45000), money.New(72000)},
+		},
+		{
+			name:    "TC9.4: No Eligible Items",
+			coupon:  newCoupon(model.CouponTypePercentage, 10, model.CouponTargeting{IncludeSKUs: []string{"HAT-1"}}),
+			items:   items,
+			wantErr: true,
+		},
+		{
+			name:    "TC9.5: Order without Items",
+			coupon:  newCoupon(model.CouponTypePercentage, 10, model.CouponTargeting{IncludeCategories: []string{"shoes"}}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := schema.CreateMockOrderRequest{Cost: money.New(330000), Items: tt.items, CreatedAt: time.Now()}
+			_, amounts, err := cs.ApplyCoupons(context.Background(), []model.Coupon{tt.coupon}, req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyCoupons() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			total := money.Zero
+			for i, line := range amounts.Lines {
+				total = total.Add(line.Amount)
+				if !line.Amount.Equal(tt.wantLineTotals[i]) {
+					t.Errorf("ApplyCoupons() line %d = %v, want %v", i, line.Amount, tt.wantLineTotals[i])
+				}
+			}
+			if !total.Equal(amounts.Subtotal) {
+				t.Errorf("ApplyCoupons() lines add up to %v, subtotal is %v", total, amounts.Subtotal)
+			}
+		})
+	}
+}
+
+func TestApplyBuyXGetYCoupon(t *testing.T) {
+	cs := NewCouponService(logger.New("test"), CouponServiceDeps{})
+	maxSets := 1
+	newCoupon := func(rules model.CouponBuyXGetY) model.Coupon {
+		return model.Coupon{
+			CouponCode: "BOGO",
+			CouponType: model.CouponTypeBuyXGetY,
+			Status:     model.CouponStatusActive,
+			ExpiredAt:  time.Now().Add(24 * time.Hour),
+			BuyXGetY:   &rules,
+		}
+	}
+	buy2A1B := model.CouponBuyXGetY{
+		Buy: model.CouponItemRule{SKUs: []string{"A"}, Quantity: 2},
+		Get: model.CouponItemRule{SKUs: []string{"B"}, Quantity: 1},
+	}
+	buy2A1A := model.CouponBuyXGetY{
+		Buy: model.CouponItemRule{SKUs: []string{"A"}, Quantity: 2},
+		Get: model.CouponItemRule{SKUs: []string{"A"}, Quantity: 1},
+	}
+	item := func(sku, category string, quantity int, unitPrice float64) schema.OrderItemRequest {
+		return schema.OrderItemRequest{SKU: sku, Category: category, Quantity: quantity, UnitPrice: money.New(unitPrice)}
+	}
+	tests := []struct {
+		name         string
+		coupon       model.Coupon
+		items        []schema.OrderItemRequest
+		wantDiscount money.Amount
+		wantErr      bool
+	}{
+		{
+			name:         "TC10.1: One Qualifying Set",
+			coupon:       newCoupon(buy2A1B),
+			items:        []schema.OrderItemRequest{item("A", "", 2, 100000), item("B", "", 1, 50000)},
+			wantDiscount: money.New(50000),
+		},
+		{
+			name:         "TC10.2: Several Qualifying Sets",
+			coupon:       newCoupon(buy2A1B),
+			items:        []schema.OrderItemRequest{item("A", "", 4, 100000), item("B", "", 3, 50000)},
+			wantDiscount: money.New(100000),
+		},
+		{
+			name:         "TC10.3: Incomplete Second Set",
+			coupon:       newCoupon(buy2A1B),
+			items:        []schema.OrderItemRequest{item("A", "", 3, 100000), item("B", "", 2, 50000)},
+			wantDiscount: money.New(50000),
+		},
+		{
+			name:         "TC10.4: Same SKU as Trigger and Reward",
+			coupon:       newCoupon(buy2A1A),
+			items:        []schema.OrderItemRequest{item("A", "", 5, 30000)},
+			wantDiscount: money.New(30000),
+		},
+		{
+			name:         "TC10.5: Same SKU with Two Sets",
+			coupon:       newCoupon(buy2A1A),
+			items:        []schema.OrderItemRequest{item("A", "", 6, 30000)},
+			wantDiscount: money.New(60000),
+		},
+		{
+			name: "TC10.6: Max Sets",
+			coupon: newCoupon(model.CouponBuyXGetY{
+				Buy:     buy2A1B.Buy,
+				Get:     buy2A1B.Get,
+				MaxSets: &maxSets,
+			}),
+			items:        []schema.OrderItemRequest{item("A", "", 4, 100000), item("B", "", 2, 50000)},
+			wantDiscount: money.New(50000),
+		},
+		{
+			name: "TC10.7: Cheapest Reward Item Is Discounted",
+			coupon: newCoupon(model.CouponBuyXGetY{
+				Buy: model.CouponItemRule{Categories: []string{"shoes"}, Quantity: 1},
+				Get: model.CouponItemRule{Categories: []string{"socks"}, Quantity: 1},
+			}),
+			items:        []schema.OrderItemRequest{item("SHOE", "shoes", 1, 500000), item("SOCK-2", "socks", 1, 20000), item("SOCK-1", "socks", 1, 10000)},
+			wantDiscount: money.New(10000),
+		},
+		{
+			name: "TC10.8: Half Price Reward",
+			coupon: newCoupon(model.CouponBuyXGetY{
+				Buy:      buy2A1B.Buy,
+				Get:      buy2A1B.Get,
+				Discount: func() *money.Amount { d := money.New(50); return &d }(),
+			}),
+			items:        []schema.OrderItemRequest{item("A", "", 2, 100000), item("B", "", 1, 50000)},
+			wantDiscount: money.New(25000),
+		},
+		{
+			name:    "TC10.9: No Qualifying Set",
+			coupon:  newCoupon(buy2A1B),
+			items:   []schema.OrderItemRequest{item("A", "", 1, 100000), item("B", "", 1, 50000)},
+			wantErr: true,
+		},
+		{
+			name:    "TC10.10: Order without Items",
+			coupon:  newCoupon(buy2A1B),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := schema.CreateMockOrderRequest{Cost: money.New(100000), Items: tt.items, CreatedAt: time.Now()}
+			if len(tt.items) > 0 {
+				req.Cost = req.ItemsTotal()
+			}
+			applied, amounts, err := cs.ApplyCoupons(context.Background(), []model.Coupon{tt.coupon}, req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyCoupons() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !applied[0].DiscountAmount.Equal(tt.wantDiscount) {
+				t.Errorf("ApplyCoupons() discount = %v, want %v", applied[0].DiscountAmount, tt.wantDiscount)
+			}
+			total := money.Zero
+			for _, line := range amounts.Lines {
+				total = total.Add(line.Amount)
+			}
+			if !total.Equal(amounts.Subtotal) {
+				t.Errorf("ApplyCoupons() lines add up to %v, subtotal is %v", total, amounts.Subtotal)
+			}
+		})
+	}
+}
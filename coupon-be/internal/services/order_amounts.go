@@ -0,0 +1,111 @@
+package services
+
+import (
+	"coupon-be/internal/model"
+	"coupon-be/internal/schema"
+	"coupon-be/pkg/money"
+)
+
+// OrderAmounts splits an order into the parts coupons discount separately:
+// the goods subtotal and the shipping fee. Orders placed with line items
+// also carry what is left of each line, which always adds up to Subtotal.
+type OrderAmounts struct {
+	Subtotal    money.Amount
+	ShippingFee money.Amount
+	Lines       []LineAmount
+}
+
+// LineAmount is what is left to pay for one line of an order.
+type LineAmount struct {
+	Item   schema.OrderItemRequest
+	Amount money.Amount
+}
+
+// DiscountAmount returns what coupons took off the line.
+func (l LineAmount) DiscountAmount() money.Amount {
+	return l.Item.Amount().Sub(l.Amount)
+}
+
+// Total returns the subtotal plus shipping.
+func (a OrderAmounts) Total() money.Amount {
+	return a.Subtotal.Add(a.ShippingFee)
+}
+
+func orderAmounts(req schema.CreateMockOrderRequest) OrderAmounts {
+	amounts := OrderAmounts{Subtotal: req.Cost, ShippingFee: req.ShippingFee}
+	for _, item := range req.Items {
+		amounts.Lines = append(amounts.Lines, LineAmount{Item: item, Amount: item.Amount()})
+	}
+	return amounts
+}
+
+// targeted returns the part of the order the coupon may discount: the lines
+// its targeting lets through, plus shipping. Orders without lines are
+// returned as they are.
+func (a OrderAmounts) targeted(coupon model.Coupon) OrderAmounts {
+	if coupon.Targeting == nil || len(a.Lines) == 0 {
+		return a
+	}
+	eligible := OrderAmounts{Subtotal: money.Zero, ShippingFee: a.ShippingFee}
+	for _, line := range a.Lines {
+		if coupon.Targeting.Matches(line.Item.SKU, line.Item.Category) {
+			eligible.Subtotal = eligible.Subtotal.Add(line.Amount)
+			eligible.Lines = append(eligible.Lines, line)
+		}
+	}
+	return eligible
+}
+
+// withDiscount applies the result of discounting the targeted part of the
+// order back to the whole order. Calculators that discount lines themselves
+// keep their breakdown; otherwise the subtotal discount is spread over the
+// targeted lines in proportion to what is left of them, and the last line
+// takes the rounding difference.
+func (a OrderAmounts) withDiscount(coupon model.Coupon, targeted, discounted OrderAmounts) OrderAmounts {
+	discount := targeted.Subtotal.Sub(discounted.Subtotal)
+	result := OrderAmounts{
+		Subtotal:    a.Subtotal.Sub(discount),
+		ShippingFee: discounted.ShippingFee,
+		Lines:       make([]LineAmount, len(a.Lines)),
+	}
+	copy(result.Lines, a.Lines)
+	if len(a.Lines) == 0 || discount.IsZero() {
+		return result
+	}
+
+	var eligible []int
+	for i, line := range a.Lines {
+		if coupon.Targeting == nil || coupon.Targeting.Matches(line.Item.SKU, line.Item.Category) {
+			eligible = append(eligible, i)
+		}
+	}
+	if discountsLines(targeted, discounted) {
+		for n, i := range eligible {
+			result.Lines[i].Amount = discounted.Lines[n].Amount
+		}
+		return result
+	}
+	remaining := discount
+	for n, i := range eligible {
+		share := remaining
+		if n < len(eligible)-1 {
+			share = discount.Mul(a.Lines[i].Amount).Div(targeted.Subtotal).RoundFor(coupon.Currency)
+		}
+		result.Lines[i].Amount = result.Lines[i].Amount.Sub(share)
+		remaining = remaining.Sub(share)
+	}
+	return result
+}
+
+// discountsLines reports whether a calculator changed the lines it was given.
+func discountsLines(targeted, discounted OrderAmounts) bool {
+	if len(targeted.Lines) != len(discounted.Lines) {
+		return false
+	}
+	for i := range targeted.Lines {
+		if !targeted.Lines[i].Amount.Equal(discounted.Lines[i].Amount) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,9 @@ import (
 	"coupon-be/config"
 	"coupon-be/internal/app"
 	"log"
+	// Coupon validity windows load IANA time zones; embed them so the binary
+	// does not depend on the zoneinfo of the image it runs in.
+	_ "time/tzdata"
 
 	_ "ariga.io/atlas-provider-gorm/gormschema"
 )
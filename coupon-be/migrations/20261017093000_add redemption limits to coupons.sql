@@ -0,0 +1,4 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `max_redemptions` int NULL, ADD COLUMN `max_redemptions_per_customer` int NULL;
+-- Modify "orders" table
+ALTER TABLE `orders` ADD COLUMN `customer_id` varchar(255) NULL, ADD INDEX `idx_orders_customer_id` (`customer_id`);
@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `min_order_amount` decimal(10,2) NULL, ADD COLUMN `max_discount_amount` decimal(10,2) NULL;
@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `starts_at` datetime NULL;
@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `status` enum('draft','active','paused','archived') NOT NULL DEFAULT "active";
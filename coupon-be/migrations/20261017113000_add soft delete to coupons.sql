@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `deleted_at` datetime(3) NULL, ADD INDEX `idx_coupons_deleted_at` (`deleted_at`);
@@ -0,0 +1,2 @@
+-- Modify "coupon_redemptions" table
+ALTER TABLE `coupon_redemptions` ADD COLUMN `auto_applied` bool NOT NULL DEFAULT 0;
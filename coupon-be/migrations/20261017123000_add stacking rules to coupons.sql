@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `stacking_group` varchar(255) NULL, ADD COLUMN `exclusive` bool NOT NULL DEFAULT 0, ADD COLUMN `priority` bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `currency` char(3) NOT NULL DEFAULT "VND";
+-- Modify "orders" table
+ALTER TABLE `orders` ADD COLUMN `currency` char(3) NOT NULL DEFAULT "VND";
@@ -0,0 +1,4 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` MODIFY COLUMN `coupon_type` enum('fixed','percentage','free_shipping') NOT NULL;
+-- Modify "orders" table
+ALTER TABLE `orders` ADD COLUMN `shipping_fee` decimal(10,2) NOT NULL DEFAULT 0.00, ADD COLUMN `shipping_discount_amount` decimal(10,2) NOT NULL DEFAULT 0.00;
@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` MODIFY COLUMN `coupon_type` varchar(32) NOT NULL;
@@ -0,0 +1,10 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `allowed_customer_ids` json NULL, ADD COLUMN `customer_segment` varchar(255) NULL;
+-- Create "customer_segments" table
+CREATE TABLE `customer_segments` (
+  `customer_id` varchar(255) NOT NULL,
+  `segment` varchar(255) NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`customer_id`, `segment`),
+  INDEX `idx_customer_segments_segment` (`segment`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
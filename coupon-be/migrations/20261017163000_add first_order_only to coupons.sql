@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `first_order_only` bool NOT NULL DEFAULT 0;
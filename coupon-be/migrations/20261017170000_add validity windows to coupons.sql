@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `validity_windows` json NULL, ADD COLUMN `time_zone` varchar(64) NULL;
@@ -0,0 +1,4 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `channels` json NULL, ADD COLUMN `payment_methods` json NULL;
+-- Modify "orders" table
+ALTER TABLE `orders` ADD COLUMN `channel` varchar(64) NULL, ADD COLUMN `payment_method` varchar(64) NULL;
@@ -0,0 +1,20 @@
+-- Create "coupon_batches" table
+CREATE TABLE `coupon_batches` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `parent_coupon_code` varchar(255) NOT NULL,
+  `quantity` bigint NOT NULL,
+  `generated_count` bigint NOT NULL DEFAULT 0,
+  `prefix` varchar(32) NOT NULL DEFAULT "",
+  `alphabet` varchar(128) NOT NULL,
+  `length` bigint NOT NULL,
+  `check_digit` bool NOT NULL DEFAULT 0,
+  `status` enum('pending','running','completed','failed') NOT NULL DEFAULT "pending",
+  `error` text NULL,
+  `completed_at` datetime NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_coupon_batches_parent_coupon_code` (`parent_coupon_code`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `parent_coupon_code` varchar(255) NULL, ADD COLUMN `batch_id` bigint unsigned NULL, ADD INDEX `idx_coupons_parent_coupon_code` (`parent_coupon_code`), ADD INDEX `idx_coupons_batch_id` (`batch_id`);
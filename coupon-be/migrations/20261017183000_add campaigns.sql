@@ -0,0 +1,18 @@
+-- Create "campaigns" table
+CREATE TABLE `campaigns` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) NOT NULL,
+  `description` text NOT NULL,
+  `owner` varchar(255) NOT NULL,
+  `budget` decimal(15,2) NULL,
+  `currency` char(3) NOT NULL DEFAULT "VND",
+  `starts_at` datetime NOT NULL,
+  `ends_at` datetime NOT NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_campaigns_deleted_at` (`deleted_at`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `campaign_id` bigint unsigned NULL, ADD INDEX `idx_coupons_campaign_id` (`campaign_id`);
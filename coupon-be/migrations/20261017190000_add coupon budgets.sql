@@ -0,0 +1,2 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `budget` decimal(15,2) NULL, ADD COLUMN `allow_partial_discount` bool NOT NULL DEFAULT 0;
@@ -0,0 +1,14 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` ADD COLUMN `requires_claim` bool NOT NULL DEFAULT 0, ADD COLUMN `claim_validity_days` int NULL;
+-- Create "wallet_coupons" table
+CREATE TABLE `wallet_coupons` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `customer_id` varchar(255) NOT NULL,
+  `coupon_code` varchar(255) NOT NULL,
+  `claimed_at` datetime NOT NULL,
+  `expires_at` datetime NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_wallet_coupons_coupon_code` (`coupon_code`),
+  UNIQUE INDEX `idx_wallet_coupons_customer_coupon` (`customer_id`, `coupon_code`),
+  CONSTRAINT `fk_wallet_coupons_coupon` FOREIGN KEY (`coupon_code`) REFERENCES `coupons` (`coupon_code`) ON UPDATE NO ACTION ON DELETE NO ACTION
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
@@ -0,0 +1,8 @@
+-- Modify "coupons" table
+ALTER TABLE `coupons` MODIFY COLUMN `coupon_value` decimal(15,2) NOT NULL, MODIFY COLUMN `min_order_amount` decimal(15,2) NULL, MODIFY COLUMN `max_discount_amount` decimal(15,2) NULL;
+-- Modify "orders" table
+ALTER TABLE `orders` MODIFY COLUMN `cost` decimal(15,2) NOT NULL, MODIFY COLUMN `discount_amount` decimal(15,2) NOT NULL DEFAULT 0.00, MODIFY COLUMN `shipping_fee` decimal(15,2) NOT NULL DEFAULT 0.00, MODIFY COLUMN `shipping_discount_amount` decimal(15,2) NOT NULL DEFAULT 0.00, MODIFY COLUMN `total_amount` decimal(15,2) NOT NULL;
+-- Modify "order_items" table
+ALTER TABLE `order_items` MODIFY COLUMN `unit_price` decimal(15,2) NOT NULL, MODIFY COLUMN `discount_amount` decimal(15,2) NOT NULL DEFAULT 0.00, MODIFY COLUMN `total_amount` decimal(15,2) NOT NULL;
+-- Modify "coupon_redemptions" table
+ALTER TABLE `coupon_redemptions` MODIFY COLUMN `discount_amount` decimal(15,2) NOT NULL;
@@ -0,0 +1,5 @@
+-- Create "customer_order_locks" table
+CREATE TABLE `customer_order_locks` (
+  `customer_id` varchar(255) NOT NULL,
+  PRIMARY KEY (`customer_id`)
+) CHARSET utf8mb4 COLLATE utf8mb4_0900_ai_ci;
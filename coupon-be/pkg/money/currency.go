@@ -0,0 +1,59 @@
+package money
+
+import "strings"
+
+// Currency is an ISO 4217 currency code such as VND or USD.
+type Currency string
+
+var defaultCurrency Currency = "VND"
+
+// minorUnits lists the decimal places of currencies that do not use the
+// usual two. Currencies missing from the table are rounded to Places.
+// Amounts are stored with Places decimals, so currencies with more minor
+// units, such as BHD or KWD, are rounded to Places as well.
+var minorUnits = map[Currency]int32{
+	"VND": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"IDR": 0,
+}
+
+// SetDefaultCurrency changes the currency assumed for coupons and orders
+// that do not name one.
+func SetDefaultCurrency(currency Currency) {
+	defaultCurrency = Currency(strings.ToUpper(string(currency)))
+}
+
+// DefaultCurrency returns the currency assumed when none is given.
+func DefaultCurrency() Currency {
+	return defaultCurrency
+}
+
+// OrDefault returns the currency, or the default currency if it is empty.
+func (c Currency) OrDefault() Currency {
+	if c == "" {
+		return defaultCurrency
+	}
+	return c
+}
+
+// MinorUnits returns the number of decimal places amounts in the currency
+// are rounded to.
+func (c Currency) MinorUnits() int32 {
+	if places, ok := minorUnits[c.OrDefault()]; ok {
+		return places
+	}
+	return Places
+}
+
+// RoundFor rounds the amount to the minor unit of the currency using the
+// configured rounding mode.
+func (a Amount) RoundFor(currency Currency) Amount {
+	return a.RoundTo(currency.MinorUnits())
+}
+
+// FitsMinorUnit reports whether the amount has no more decimal places than
+// the minor unit of the currency, so it is stored and charged as given.
+func (a Amount) FitsMinorUnit(currency Currency) bool {
+	return a.d.Equal(a.d.Round(currency.MinorUnits()))
+}
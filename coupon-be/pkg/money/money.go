@@ -0,0 +1,203 @@
+// Package money implements an exact decimal amount for prices and discounts.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingMode decides how amounts are rounded to the minor unit.
+type RoundingMode string
+
+const (
+	// RoundHalfUp rounds halves away from zero.
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the nearest even digit (banker's rounding).
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundUp rounds away from zero.
+	RoundUp RoundingMode = "up"
+	// RoundDown rounds towards zero.
+	RoundDown RoundingMode = "down"
+)
+
+// Places is the number of decimal places amounts are stored with.
+const Places int32 = 2
+
+var roundingMode = RoundHalfUp
+
+// SetRoundingMode changes the rounding mode used by Amount.Round.
+func SetRoundingMode(mode RoundingMode) error {
+	switch mode {
+	case RoundHalfUp, RoundHalfEven, RoundUp, RoundDown:
+		roundingMode = mode
+		return nil
+	default:
+		return fmt.Errorf("unknown rounding mode %q", mode)
+	}
+}
+
+// Amount is an exact decimal amount. The zero value is 0.
+type Amount struct {
+	d decimal.Decimal
+}
+
+// Zero is the zero amount.
+var Zero = Amount{}
+
+// New returns the amount closest to the given float.
+func New(value float64) Amount {
+	return Amount{d: decimal.NewFromFloat(value)}
+}
+
+// NewFromInt returns an amount for a whole number.
+func NewFromInt(value int64) Amount {
+	return Amount{d: decimal.NewFromInt(value)}
+}
+
+// NewFromString parses an amount such as "15000" or "79999.99".
+func NewFromString(value string) (Amount, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return Amount{}, err
+	}
+	return Amount{d: d}, nil
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return Amount{d: a.d.Add(b.d)}
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{d: a.d.Sub(b.d)}
+}
+
+func (a Amount) Mul(b Amount) Amount {
+	return Amount{d: a.d.Mul(b.d)}
+}
+
+// Div returns a divided by b. It panics if b is zero.
+func (a Amount) Div(b Amount) Amount {
+	return Amount{d: a.d.Div(b.d)}
+}
+
+// Percent returns percent % of the amount. The result is exact; callers
+// round it once to the minor unit they need.
+func (a Amount) Percent(percent Amount) Amount {
+	return Amount{d: a.d.Mul(percent.d).Div(decimal.NewFromInt(100))}
+}
+
+// Round rounds the amount to Places using the configured rounding mode.
+func (a Amount) Round() Amount {
+	return a.RoundTo(Places)
+}
+
+// RoundTo rounds the amount to the given number of decimal places using the
+// configured rounding mode.
+func (a Amount) RoundTo(places int32) Amount {
+	switch roundingMode {
+	case RoundHalfEven:
+		return Amount{d: a.d.RoundBank(places)}
+	case RoundUp:
+		return Amount{d: a.d.RoundUp(places)}
+	case RoundDown:
+		return Amount{d: a.d.RoundDown(places)}
+	default:
+		return Amount{d: a.d.Round(places)}
+	}
+}
+
+func (a Amount) Cmp(b Amount) int {
+	return a.d.Cmp(b.d)
+}
+
+func (a Amount) Equal(b Amount) bool {
+	return a.d.Equal(b.d)
+}
+
+func (a Amount) LessThan(b Amount) bool {
+	return a.d.LessThan(b.d)
+}
+
+func (a Amount) GreaterThan(b Amount) bool {
+	return a.d.GreaterThan(b.d)
+}
+
+func (a Amount) IsZero() bool {
+	return a.d.IsZero()
+}
+
+func (a Amount) IsNegative() bool {
+	return a.d.IsNegative()
+}
+
+// Min returns the smaller of two amounts.
+func Min(a, b Amount) Amount {
+	if a.LessThan(b) {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of two amounts.
+func Max(a, b Amount) Amount {
+	if a.GreaterThan(b) {
+		return a
+	}
+	return b
+}
+
+// Float64 returns the nearest float. It is meant for validation and logging,
+// never for arithmetic.
+func (a Amount) Float64() float64 {
+	return a.d.InexactFloat64()
+}
+
+func (a Amount) String() string {
+	return a.d.String()
+}
+
+// StringFixed formats the amount with exactly Places decimal places.
+func (a Amount) StringFixed() string {
+	return a.d.StringFixed(Places)
+}
+
+// MarshalJSON encodes the amount as a JSON number.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.d.String()), nil
+}
+
+// UnmarshalJSON accepts both JSON numbers and numeric strings.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	return a.d.UnmarshalJSON(data)
+}
+
+// MarshalBinary encodes the amount as its decimal string, which is how it is
+// stored in the Redis cache.
+func (a Amount) MarshalBinary() ([]byte, error) {
+	return []byte(a.d.String()), nil
+}
+
+func (a *Amount) UnmarshalBinary(data []byte) error {
+	d, err := decimal.NewFromString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return err
+	}
+	a.d = d
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (a Amount) Value() (driver.Value, error) {
+	return a.d.String(), nil
+}
+
+// Scan implements sql.Scanner.
+func (a *Amount) Scan(value interface{}) error {
+	return a.d.Scan(value)
+}
@@ -0,0 +1,124 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRound(t *testing.T) {
+	defer SetRoundingMode(RoundHalfUp)
+	tests := []struct {
+		name  string
+		mode  RoundingMode
+		value string
+		want  string
+	}{
+		{name: "half up rounds halves away from zero", mode: RoundHalfUp, value: "10.125", want: "10.13"},
+		{name: "half up rounds below half down", mode: RoundHalfUp, value: "10.124", want: "10.12"},
+		{name: "half even rounds halves to even", mode: RoundHalfEven, value: "10.125", want: "10.12"},
+		{name: "half even rounds odd halves up", mode: RoundHalfEven, value: "10.135", want: "10.14"},
+		{name: "up rounds away from zero", mode: RoundUp, value: "10.121", want: "10.13"},
+		{name: "down truncates", mode: RoundDown, value: "10.129", want: "10.12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetRoundingMode(tt.mode); err != nil {
+				t.Fatalf("SetRoundingMode() error = %v", err)
+			}
+			value, err := NewFromString(tt.value)
+			if err != nil {
+				t.Fatalf("NewFromString() error = %v", err)
+			}
+			if got := value.Round().String(); got != tt.want {
+				t.Errorf("Round() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRoundingModeRejectsUnknownMode(t *testing.T) {
+	if err := SetRoundingMode("nearest"); err == nil {
+		t.Errorf("SetRoundingMode() error = nil, want error")
+	}
+}
+
+func TestPercent(t *testing.T) {
+	// 20% of 99999.99 is 19999.998, which rounds to 20000.
+	amount := New(99999.99)
+	discount := amount.Percent(New(20)).Round()
+	if got := discount.String(); got != "20000" {
+		t.Errorf("Percent() got = %v, want 20000", got)
+	}
+	if got := amount.Sub(discount).String(); got != "79999.99" {
+		t.Errorf("Percent() total = %v, want 79999.99", got)
+	}
+	if got := New(0.1).Add(New(0.2)).String(); got != "0.3" {
+		t.Errorf("Add() got = %v, want 0.3", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var got struct {
+		Number Amount `json:"number"`
+		Text   Amount `json:"text"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 79999.99, "text": "15000.50"}`), &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !got.Number.Equal(New(79999.99)) || !got.Text.Equal(New(15000.5)) {
+		t.Errorf("json.Unmarshal() got = %v, %v", got.Number, got.Text)
+	}
+	encoded, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(encoded) != `{"number":79999.99,"text":15000.5}` {
+		t.Errorf("json.Marshal() got = %s", encoded)
+	}
+}
+
+func TestRoundFor(t *testing.T) {
+	tests := []struct {
+		currency Currency
+		value    string
+		want     string
+	}{
+		{currency: "VND", value: "14999.5", want: "15000"},
+		{currency: "USD", value: "14999.555", want: "14999.56"},
+		{currency: "KWD", value: "1.2345", want: "1.23"}, // stored with Places decimals
+		{currency: "", value: "10.5", want: "11"},        // default currency is VND
+	}
+	for _, tt := range tests {
+		value, err := NewFromString(tt.value)
+		if err != nil {
+			t.Fatalf("NewFromString() error = %v", err)
+		}
+		if got := value.RoundFor(tt.currency).String(); got != tt.want {
+			t.Errorf("RoundFor(%q) got = %v, want %v", tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFitsMinorUnit(t *testing.T) {
+	tests := []struct {
+		currency Currency
+		value    string
+		want     bool
+	}{
+		{currency: "VND", value: "100000", want: true},
+		{currency: "VND", value: "100.55", want: false},
+		{currency: "USD", value: "100.55", want: true},
+		{currency: "USD", value: "100.555", want: false},
+		{currency: "USD", value: "100.550", want: true},
+		{currency: "", value: "10.5", want: false}, // default currency is VND
+	}
+	for _, tt := range tests {
+		value, err := NewFromString(tt.value)
+		if err != nil {
+			t.Fatalf("NewFromString() error = %v", err)
+		}
+		if got := value.FitsMinorUnit(tt.currency); got != tt.want {
+			t.Errorf("FitsMinorUnit(%q) of %s got = %v, want %v", tt.currency, tt.value, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"coupon-be/internal/model"
+	"coupon-be/pkg/money"
 	"fmt"
 	"time"
 
@@ -34,7 +35,7 @@ func main() {
 			CouponType:  couponType,
 			Usage:       couponUsage,
 			ExpiredAt:   time.Now().AddDate(0, 0, 10),
-			CouponValue: float64(i * 10),
+			CouponValue: money.NewFromInt(int64(i * 10)),
 		})
 		err = db.Create(&SEED_DATA[i]).Error
 		if err != nil {
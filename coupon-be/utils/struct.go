@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"database/sql/driver"
 	"reflect"
 )
 
@@ -73,13 +74,13 @@ func StructToMapGetNull(req any) map[string]any {
 		}
 
 		// Handle nested structs
-		if field.Kind() == reflect.Struct && field.Type().Name() != "Time" {
+		if field.Kind() == reflect.Struct && field.Type().Name() != "Time" && !isValuer(field.Type()) {
 			result[jsonTag] = StructToMapGetNull(field.Interface())
 			continue
 		}
 
 		// Handle slices (skip slices of structs)
-		if field.Kind() == reflect.Slice {
+		if field.Kind() == reflect.Slice && !isValuer(field.Type()) {
 			if elemType := field.Type().Elem(); elemType.Kind() == reflect.Struct {
 				continue
 			}
@@ -89,11 +90,11 @@ func StructToMapGetNull(req any) map[string]any {
 		if field.Kind() == reflect.Ptr {
 			if field.IsNil() {
 				result[jsonTag] = nil
-			} else if field.Elem().Kind() == reflect.Slice {
+			} else if field.Elem().Kind() == reflect.Slice && !isValuer(field.Type().Elem()) {
 				if elemType := field.Elem().Type().Elem(); elemType.Kind() != reflect.Struct {
 					result[jsonTag] = field.Elem().Interface()
 				}
-			} else if field.Elem().Kind() == reflect.Struct && field.Type().Elem().Name() != "Time" {
+			} else if field.Elem().Kind() == reflect.Struct && field.Type().Elem().Name() != "Time" && !isValuer(field.Type().Elem()) {
 				result[jsonTag] = StructToMapGetNull(field.Elem().Interface())
 			} else {
 				result[jsonTag] = field.Elem().Interface()
@@ -142,3 +143,11 @@ func StructToMapString(req any) map[string]string {
 
 	return result
 }
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// isValuer reports whether values of t are stored as a single column, like
+// money amounts, rather than as nested structs.
+func isValuer(t reflect.Type) bool {
+	return t.Implements(valuerType) || reflect.PointerTo(t).Implements(valuerType)
+}